@@ -1,12 +1,16 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"maps"
+	"os"
 	"slices"
 	"strings"
 	"time"
 
+	"github.com/google/go-github/v89/github"
 	owners "github.com/multimediallc/codeowners-plus/internal/config"
 	"github.com/multimediallc/codeowners-plus/internal/git"
 	gh "github.com/multimediallc/codeowners-plus/internal/github"
@@ -22,6 +26,10 @@ type OutputData struct {
 	StillRequired []string            `json:"still_required"`
 	Success       bool                `json:"success"`
 	Message       string              `json:"message"`
+	// Bypasses are the admin bypasses applied to the evaluation
+	Bypasses []*gh.Bypass `json:"bypasses,omitempty"`
+	// Plan lists the GitHub changes which were skipped in dry-run mode
+	Plan []*PlannedAction `json:"plan,omitempty"`
 }
 
 func NewOutputData(co codeowners.CodeOwners) *OutputData {
@@ -51,14 +59,34 @@ func (od *OutputData) UpdateOutputData(success bool, message string, stillRequir
 
 // Config holds the application configuration
 type Config struct {
-	Token         string
-	RepoDir       string
-	PR            int
-	Repo          string
-	Verbose       bool
-	Quiet         bool
+	Token     string
+	RepoDir   string
+	PR        int
+	Repo      string
+	Verbose   bool
+	Quiet     bool
+	CacheFile string
+	CacheTTL  time.Duration
+	// Cache is shared between runs by long-running processes, and takes precedence over CacheFile
+	Cache         gh.Cache
+	MaxRetries    int
 	InfoBuffer    io.Writer
 	WarningBuffer io.Writer
+	// GitHub App credentials, used instead of Token when AppID is set
+	AppID             int64
+	AppInstallationID int64
+	AppPrivateKey     string
+	// GitHub Enterprise Server API and upload URLs (github.com when empty)
+	APIURL    string
+	UploadURL string
+	// MergeGroup is set when evaluating a PR in the merge queue
+	MergeGroup *MergeGroup
+	// Command is set when the run was triggered by a slash command in a PR comment
+	Command *Command
+	// BypassAuditLog is a file every applied bypass is appended to as a JSON line
+	BypassAuditLog string
+	// DryRun evaluates the PR without changing anything on GitHub, recording the changes as a plan
+	DryRun bool
 }
 
 // App represents the application with its dependencies
@@ -68,21 +96,33 @@ type App struct {
 	client     gh.Client
 	codeowners codeowners.CodeOwners
 	gitDiff    git.Diff
+	// unavailable holds the lowercased logins of users who are out of office or busy
+	unavailable f.Set[string]
+	// skippedReviewers were not requested for review because of their review load
+	skippedReviewers []codeowners.Slug
+	// optionalReviewers are listed in the review status comment with comments.fold_cc
+	optionalReviewers []string
+	// ownedFiles maps every required group to its files, before approvals are applied
+	ownedFiles map[string]codeowners.ReviewerGroups
+	bypassed   bool
+	// bypasses are the admin bypasses found for the PR, scoped ones unless bypassed is set
+	bypasses  []*gh.Bypass
+	templates commentTemplates
 }
 
 // New creates a new App instance with the given configuration
 func New(cfg Config) (*App, error) {
-	repoSplit := strings.Split(cfg.Repo, "/")
-	if len(repoSplit) != 2 {
-		return nil, fmt.Errorf("invalid repo name: %s", cfg.Repo)
-	}
-	owner := repoSplit[0]
-	repo := repoSplit[1]
-
-	client, err := gh.NewClient(owner, repo, cfg.Token)
+	client, err := NewClient(cfg)
 	if err != nil {
 		return nil, err
 	}
+	if cfg.MergeGroup != nil {
+		// Nobody should be notified about a PR which is already being merged
+		cfg.Quiet = true
+	}
+	if cfg.DryRun {
+		client = newDryRunClient(client)
+	}
 	app := &App{
 		config: &cfg,
 		client: client,
@@ -91,6 +131,106 @@ func New(cfg Config) (*App, error) {
 	return app, nil
 }
 
+// NewClient creates the GitHub client for the configured repository, authentication and API options
+func NewClient(cfg Config) (gh.Client, error) {
+	owner, repo, err := cfg.splitRepo()
+	if err != nil {
+		return nil, err
+	}
+
+	clientOpts := []gh.ClientOption{
+		gh.WithMaxRetries(cfg.MaxRetries),
+		gh.WithEnterpriseURLs(cfg.APIURL, cfg.UploadURL),
+	}
+	if cfg.Cache != nil {
+		clientOpts = append(clientOpts, gh.WithCache(cfg.Cache, cfg.CacheTTL))
+	} else if cfg.CacheFile != "" {
+		cache, err := gh.NewFileCache(cfg.CacheFile)
+		if err != nil {
+			return nil, err
+		}
+		clientOpts = append(clientOpts, gh.WithCache(cache, cfg.CacheTTL))
+	}
+	appCreds, err := cfg.AppCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if appCreds != nil {
+		clientOpts = append(clientOpts, gh.WithAppAuth(*appCreds))
+	}
+
+	client, err := gh.NewClient(owner, repo, cfg.Token, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.WarningBuffer != nil {
+		client.SetWarningBuffer(cfg.WarningBuffer)
+	}
+	if cfg.InfoBuffer != nil {
+		client.SetInfoBuffer(cfg.InfoBuffer)
+	}
+	return client, nil
+}
+
+func (cfg Config) splitRepo() (string, string, error) {
+	repoSplit := strings.Split(cfg.Repo, "/")
+	if len(repoSplit) != 2 {
+		return "", "", fmt.Errorf("invalid repo name: %s", cfg.Repo)
+	}
+	return repoSplit[0], repoSplit[1], nil
+}
+
+// GitToken returns a token which authenticates git fetches from the repository
+func (cfg Config) GitToken(ctx context.Context) (string, error) {
+	appCreds, err := cfg.AppCredentials()
+	if err != nil {
+		return "", err
+	}
+	if appCreds == nil {
+		return cfg.Token, nil
+	}
+	owner, repo, err := cfg.splitRepo()
+	if err != nil {
+		return "", err
+	}
+	token, err := gh.AppInstallationToken(ctx, owner, repo, *appCreds, gh.WithEnterpriseURLs(cfg.APIURL, cfg.UploadURL))
+	if err != nil {
+		return "", fmt.Errorf("failed to create a token for git: %w", err)
+	}
+	return token, nil
+}
+
+// AppCredentials returns the GitHub App credentials to authenticate with, or nil when using a token
+func (cfg Config) AppCredentials() (*gh.AppCredentials, error) {
+	if cfg.AppID == 0 {
+		return nil, nil
+	}
+	privateKey, err := loadAppPrivateKey(cfg.AppPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &gh.AppCredentials{
+		AppID:          cfg.AppID,
+		InstallationID: cfg.AppInstallationID,
+		PrivateKey:     privateKey,
+	}, nil
+}
+
+// loadAppPrivateKey accepts either the PEM encoded private key itself or a path to the key file
+func loadAppPrivateKey(privateKey string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(privateKey), "-----BEGIN") {
+		return []byte(privateKey), nil
+	}
+	if privateKey == "" {
+		return nil, fmt.Errorf("app private key is required when using GitHub App authentication")
+	}
+	key, err := os.ReadFile(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("error reading app private key: %w", err)
+	}
+	return key, nil
+}
+
 func (a *App) printDebug(format string, args ...interface{}) {
 	if a.config.Verbose {
 		_, _ = fmt.Fprintf(a.config.InfoBuffer, format, args...)
@@ -102,17 +242,28 @@ func (a *App) printWarn(format string, args ...interface{}) {
 }
 
 // Run executes the application logic
-func (a *App) Run() (*OutputData, error) {
+func (a *App) Run() (outputData *OutputData, err error) {
 	// Initialize PR
 	if err := a.client.InitPR(a.config.PR); err != nil {
 		return &OutputData{}, fmt.Errorf("InitPR Error: %v", err)
 	}
 	a.printDebug("PR: %d\n", a.client.PR().GetNumber())
 
+	baseSHA := a.client.PR().Base.GetSHA()
+	headSHA := a.client.PR().Head.GetSHA()
+	mergeGroup := a.config.MergeGroup
+	if mergeGroup != nil {
+		// Evaluate the change as it will be merged, and report the result on the merge group commit
+		a.printDebug("Merge group: %s (%s...%s)\n", mergeGroup.HeadRef, mergeGroup.BaseSHA, mergeGroup.HeadSHA)
+		baseSHA = mergeGroup.BaseSHA
+		headSHA = mergeGroup.HeadSHA
+		a.client.SetStatusSHA(mergeGroup.HeadSHA)
+	}
+
 	// Create file reader for base ref to prevent PR authors from modifying config or .codeowners
 	// This ensures the security policy comes from the protected branch, not the PR branch
-	baseFileReader := git.NewGitRefFileReader(a.client.PR().Base.GetSHA(), a.config.RepoDir)
-	a.printDebug("Using base ref %s for codeowners.toml and .codeowners files\n", a.client.PR().Base.GetSHA())
+	baseFileReader := git.NewGitRefFileReader(baseSHA, a.config.RepoDir)
+	a.printDebug("Using base ref %s for codeowners.toml and .codeowners files\n", baseSHA)
 
 	// Read config from base ref
 	conf, err := owners.ReadConfig(a.config.RepoDir, baseFileReader)
@@ -120,11 +271,65 @@ func (a *App) Run() (*OutputData, error) {
 		a.printWarn("Error reading codeowners.toml - using default config\n")
 	}
 	a.Conf = conf
+	if mergeGroup != nil {
+		// The PR already has its approvals, and only the merge commit is checked in the queue.
+		// Approvals are carried over as is, since review commits may not be part of the merge group.
+		conf.Enforcement.Approval = false
+		conf.DisableSmartDismissal = true
+	}
+
+	// Handle the slash command which triggered the run, if any
+	if command := a.config.Command; command != nil {
+		evaluate, err := a.runCommand(command, baseFileReader)
+		if err != nil {
+			return &OutputData{}, err
+		}
+		if !evaluate {
+			return &OutputData{Success: true, Message: fmt.Sprintf("Handled %s", command.Text), Plan: a.Plan()}, nil
+		}
+	}
+
+	// Mark the commit status as pending until the evaluation completes, and as errored if it fails
+	// so that it is never left pending
+	if conf.Enforcement.CommitStatus {
+		if err := a.setCommitStatus(gh.CommitStatePending, "Evaluating codeowners reviews"); err != nil {
+			return &OutputData{}, fmt.Errorf("SetCommitStatus Error: %v", err)
+		}
+		defer func() {
+			if err != nil {
+				a.reportCommitStatusError(err)
+			}
+		}()
+	}
+
+	// Only the PR named by the merge queue branch is evaluated, so groups with other PRs are rejected
+	if mergeGroup != nil {
+		subjects, err := git.FirstParentSubjects(a.config.RepoDir, baseSHA, headSHA)
+		if err != nil {
+			return &OutputData{}, fmt.Errorf("FirstParentSubjects Error: %v", err)
+		}
+		if err := mergeGroup.CheckSinglePR(a.client.PR().GetCommits(), subjects); err != nil {
+			return &OutputData{}, err
+		}
+	}
+
+	// Load the offline team roster, if configured, so team ownership can be resolved without org read access
+	if conf.TeamRoster != "" {
+		roster, err := owners.ReadTeamRoster(a.config.RepoDir, conf.TeamRoster, baseFileReader)
+		if err != nil {
+			a.printWarn("WARNING: Error reading team roster %s - falling back to GitHub API: %v\n", conf.TeamRoster, err)
+		} else {
+			a.printDebug("Using team roster %s\n", conf.TeamRoster)
+			a.client.SetTeamRoster(roster)
+		}
+	}
+
+	a.loadCommentTemplates(conf.Comments, baseFileReader)
 
 	// Setup diff context
 	diffContext := git.DiffContext{
-		Base:       a.client.PR().Base.GetSHA(),
-		Head:       a.client.PR().Head.GetSHA(),
+		Base:       baseSHA,
+		Head:       headSHA,
 		Dir:        a.config.RepoDir,
 		IgnoreDirs: conf.Ignore,
 	}
@@ -150,7 +355,7 @@ func (a *App) Run() (*OutputData, error) {
 		}
 
 		// Create head file reader and codeowners from head ref
-		headFileReader := git.NewGitRefFileReader(a.client.PR().Head.GetSHA(), a.config.RepoDir)
+		headFileReader := git.NewGitRefFileReader(headSHA, a.config.RepoDir)
 		headCodeOwners, err := codeowners.New(a.config.RepoDir, gitDiff.AllChanges(), headFileReader, a.config.WarningBuffer)
 		if err != nil {
 			return &OutputData{}, fmt.Errorf("NewCodeOwners (head) Error: %v", err)
@@ -169,9 +374,25 @@ func (a *App) Run() (*OutputData, error) {
 	a.codeowners = codeOwners
 
 	// Initialize user reviewer map
-	if err := a.client.InitUserReviewerMap(codeowners.OriginalStrings(codeOwners.AllRequired().Flatten())); err != nil {
+	if conf.ChildTeamDepth > 0 {
+		a.printDebug("Resolving child teams up to %d level(s) deep\n", conf.ChildTeamDepth)
+		a.client.SetChildTeamDepth(conf.ChildTeamDepth)
+	}
+	// Backups are resolved along with the owners, since their approvals count for unavailable owners
+	reviewerNames := codeOwners.AllRequired().Flatten()
+	for _, backups := range backupOwners(conf.Availability) {
+		for _, backup := range backups {
+			if !codeowners.ContainsSlug(reviewerNames, backup) {
+				reviewerNames = append(reviewerNames, backup)
+			}
+		}
+	}
+	if err := a.client.InitUserReviewerMap(codeowners.OriginalStrings(reviewerNames)); err != nil {
 		return &OutputData{}, fmt.Errorf("InitUserReviewerMap Error: %v", err)
 	}
+	if err := a.applyAvailability(conf.Availability, baseFileReader); err != nil {
+		return &OutputData{}, err
+	}
 
 	// Set author
 	author := fmt.Sprintf("@%s", a.client.PR().User.GetLogin())
@@ -199,7 +420,7 @@ func (a *App) Run() (*OutputData, error) {
 	if a.config.Verbose {
 		a.printFileOwners(codeOwners)
 	}
-	outputData := NewOutputData(a.codeowners)
+	outputData = NewOutputData(a.codeowners)
 
 	// Process approvals and reviewers
 	success, message, stillRequired, err := a.processApprovalsAndReviewers()
@@ -208,15 +429,74 @@ func (a *App) Run() (*OutputData, error) {
 	}
 
 	outputData.UpdateOutputData(success, message, stillRequired)
+	outputData.Bypasses = a.bypasses
+	if a.config.DryRun {
+		a.printDebug("Dry run: not writing the bypass audit log\n")
+	} else if err := a.writeBypassAuditLog(success); err != nil {
+		a.printWarn("WARNING: Error writing bypass audit log: %v\n", err)
+	}
 
+	// Report the result as a check run if enabled
+	if conf.Enforcement.CheckRun {
+		if err := a.reportCheckRun(outputData); err != nil {
+			return outputData, fmt.Errorf("CreateCheckRun Error: %v", err)
+		}
+	}
+
+	// Report the result as a commit status if enabled
+	if conf.Enforcement.CommitStatus {
+		state := gh.CommitStateFailure
+		if outputData.Success {
+			state = gh.CommitStateSuccess
+		}
+		if err := a.setCommitStatus(state, commitStatusDescription(outputData.Message)); err != nil {
+			return outputData, fmt.Errorf("SetCommitStatus Error: %v", err)
+		}
+	}
+
+	if command := a.config.Command; command != nil && command.Name == CommandRecheck {
+		if err := a.replyToCommand(command, fmt.Sprintf("Rechecked codeowners reviews:\n```\n%s\n```", outputData.Message)); err != nil {
+			return outputData, err
+		}
+	}
+
+	outputData.Plan = a.Plan()
 	return outputData, nil
 }
 
+const defaultCommitStatusContext = "codeowners-plus"
+
+func (a *App) setCommitStatus(state, description string) error {
+	statusContext := a.Conf.Enforcement.CommitStatusContext
+	if statusContext == "" {
+		statusContext = defaultCommitStatusContext
+	}
+	a.printDebug("Setting commit status %q to %s: %s\n", statusContext, state, description)
+	return a.client.SetCommitStatus(statusContext, state, description)
+}
+
+// reportCommitStatusError replaces the pending commit status when the evaluation fails
+func (a *App) reportCommitStatusError(evalErr error) {
+	description := commitStatusDescription("Codeowners evaluation failed: " + evalErr.Error())
+	if err := a.setCommitStatus(gh.CommitStateError, description); err != nil {
+		a.printWarn("WARNING: Error reporting the failed evaluation as a commit status: %v\n", err)
+	}
+}
+
+// commitStatusDescription flattens a multi-line result message into the single line a commit status displays
+func commitStatusDescription(message string) string {
+	lines := f.Map(strings.Split(message, "\n"), func(line string) string {
+		return strings.TrimPrefix(strings.TrimSpace(line), "- ")
+	})
+	return strings.Join(f.Filtered(lines, func(line string) bool { return line != "" }), " ")
+}
+
 func (a *App) processApprovalsAndReviewers() (bool, string, []string, error) {
 	message := ""
 
 	// Get all required owners before filtering
 	allRequiredOwners := a.codeowners.AllRequired()
+	a.ownedFiles = a.codeowners.FileRequired()
 	allRequiredOwnerNames := allRequiredOwners.Flatten()
 	a.printDebug("All Required Owners: %s\n", codeowners.OriginalStrings(allRequiredOwnerNames))
 
@@ -224,22 +504,28 @@ func (a *App) processApprovalsAndReviewers() (bool, string, []string, error) {
 	allOptionalReviewerNames := a.codeowners.AllOptional().Flatten()
 	allOptionalReviewerNames = codeowners.FilterOutNames(allOptionalReviewerNames, allRequiredOwnerNames)
 	a.printDebug("All Optional Reviewers: %s\n", codeowners.OriginalStrings(allOptionalReviewerNames))
+	a.optionalReviewers = codeowners.OriginalStrings(allOptionalReviewerNames)
 
 	// Get current approvals
 	ghApprovals, err := a.client.GetCurrentReviewerApprovals()
 	if err != nil {
 		return false, message, nil, fmt.Errorf("GetCurrentApprovals Error: %v", err)
 	}
-
-	// Check for bypass approvals
-	var allowedBypassUsers []string
-	if a.Conf.AdminBypass != nil && a.Conf.AdminBypass.Enabled {
-		allowedBypassUsers = a.Conf.AdminBypass.AllowedUsers
+	if a.Conf.CommentApprovals {
+		ghApprovals, err = a.withCommentApprovals(ghApprovals)
+		if err != nil {
+			return false, message, nil, err
+		}
 	}
-	hasValidBypass, err := a.client.ContainsValidBypassApproval(allowedBypassUsers)
+
+	// Check for bypass approvals and comments
+	bypasses, err := a.findBypasses()
 	if err != nil {
-		return false, message, nil, fmt.Errorf("ContainsValidBypassApproval Error: %v", err)
+		return false, message, nil, err
 	}
+	a.bypasses = bypasses
+	hasValidBypass := slices.ContainsFunc(bypasses, isFullBypass)
+	a.bypassed = hasValidBypass
 
 	a.printDebug("Current Approvals: %+v\n", ghApprovals)
 
@@ -257,6 +543,9 @@ func (a *App) processApprovalsAndReviewers() (bool, string, []string, error) {
 	if err != nil {
 		return false, message, nil, err
 	}
+	if !hasValidBypass {
+		a.applyBypassScopes(bypasses)
+	}
 
 	// Request reviews from required owners
 	err = a.requestReviews()
@@ -291,6 +580,12 @@ func (a *App) processApprovalsAndReviewers() (bool, string, []string, error) {
 	if err != nil {
 		return false, message, nil, fmt.Errorf("failed to add optional CC comment: %w", err)
 	}
+	if err := a.addInlineOwnerComments(); err != nil {
+		a.printWarn("WARNING: Error adding inline owner comments: %v\n", err)
+	}
+	if err := a.applyOwnerLabels(slices.Concat(allRequiredOwnerNames, allOptionalReviewerNames)); err != nil {
+		a.printWarn("WARNING: Error applying owner labels: %v\n", err)
+	}
 
 	// If we have a valid bypass, approve with token owner if needed and return success
 	if hasValidBypass {
@@ -303,7 +598,7 @@ func (a *App) processApprovalsAndReviewers() (bool, string, []string, error) {
 				a.printWarn("Warning: Failed to approve PR with token owner during bypass: %v\n", err)
 			}
 		}
-		message = "SUCCESS: Codeowners requirements bypassed by administrator"
+		message = "SUCCESS: Codeowners requirements bypassed by " + bypassMessage(bypasses)
 		return true, message, []string{}, nil
 	}
 
@@ -382,55 +677,56 @@ func (a *App) addReviewStatusComment(allRequiredOwners codeowners.ReviewerGroups
 		return nil
 	}
 
-	var commentPrefix = "Codeowners approval required for this PR:\n"
-
 	hasHighPriority, err := a.client.IsInLabels(a.Conf.HighPriorityLabels)
 	if err != nil {
 		a.printWarn("WARNING: Error checking high priority labels: %v\n", err)
-	} else if hasHighPriority {
-		commentPrefix = "❗High Prio❗\n\n" + commentPrefix
 	}
 
-	comment := commentPrefix + allRequiredOwners.ToCommentString(true)
-
-	if maxReviewsMet {
-		comment += "\n\nThe PR has received the max number of required reviews. No further action is required."
+	ownedFiles := a.ownedFiles
+	if ownedFiles == nil {
+		ownedFiles = a.codeowners.FileRequired()
 	}
-
-	if minReviewsNeeded > 0 {
-		approvalText := "approval"
-		if minReviewsNeeded-currentApprovals > 1 {
-			approvalText = "approvals"
-		}
-		comment += fmt.Sprintf(
-			"\n\nMinimum review requirement not met. Need %d reviews, found %d. Reviews have been re-requested from owning teams, but any additional %s can satisfy minimum.",
-			minReviewsNeeded,
-			currentApprovals,
-			approvalText,
-		)
+	data := ReviewStatusData{
+		Groups:           reviewStatusGroups(allRequiredOwners, ownedFiles),
+		HighPriority:     err == nil && hasHighPriority,
+		MaxReviewsMet:    maxReviewsMet,
+		MinReviewsNeeded: minReviewsNeeded,
+		CurrentApprovals: currentApprovals,
+		MissingApprovals: minReviewsNeeded - currentApprovals,
+		Bypassed:         a.bypassed,
+		Bypasses:         a.bypasses,
+		SkippedReviewers: codeowners.OriginalStrings(a.skippedReviewers),
+		Detailed:         a.Conf.DetailedReviewers,
 	}
-
-	if a.Conf.DetailedReviewers {
-		comment += fmt.Sprintf("\n\n<details><summary>Show detailed file reviewers</summary>\n\n%s\n</details>", a.getFileOwnersMapToString(a.codeowners.FileRequired()))
+	if a.Conf.ReviewRequests != nil {
+		data.MaxOpenReviewsPerUser = a.Conf.ReviewRequests.MaxOpenReviewsPerUser
+	}
+	if data.Detailed {
+		data.Files = fileReviewers(a.codeowners.FileRequired())
 	}
+	if a.foldCc() {
+		data.CcReviewers = a.optionalReviewers
+	}
+	comment, err := a.renderComment(a.templates.reviewStatus, defaultReviewStatus, data)
+	if err != nil {
+		return fmt.Errorf("failed to render review status comment: %w", err)
+	}
+	comment = withCommentMarker(commentKindReviewStatus, comment)
 
-	fiveDaysAgo := time.Now().AddDate(0, 0, -5)
-	existingComment, existingFound, err := a.client.FindExistingComment(commentPrefix, &fiveDaysAgo)
+	existingComment, supersededComments, err := a.findReviewStatusComment()
 	if err != nil {
-		return fmt.Errorf("FindExistingComment Error: %v", err)
+		return fmt.Errorf("FindComments Error: %v", err)
 	}
 
-	if existingFound {
-		if found, _ := a.client.IsInComments(comment, &fiveDaysAgo); found {
-			// we don't need to update the comment
-			return nil
-		}
-		a.printDebug("Updating existing review status comment\n")
-		err = a.client.UpdateComment(existingComment, comment)
-		if err != nil {
-			return fmt.Errorf("UpdateComment Error: %v", err)
+	if existingComment != nil {
+		if existingComment.GetBody() != comment {
+			a.printDebug("Updating existing review status comment\n")
+			err = a.client.UpdateComment(existingComment.GetID(), comment)
+			if err != nil {
+				return fmt.Errorf("UpdateComment Error: %v", err)
+			}
 		}
-	} else if len(allRequiredOwners) > 0 {
+	} else if len(allRequiredOwners) > 0 || len(data.CcReviewers) > 0 {
 		a.printDebug("Adding new review status comment: %q\n", comment)
 		err = a.client.AddComment(comment)
 		if err != nil {
@@ -438,13 +734,53 @@ func (a *App) addReviewStatusComment(allRequiredOwners codeowners.ReviewerGroups
 		}
 	}
 
+	a.removeOutdatedComments(supersededComments)
 	return nil
 }
 
+// findReviewStatusComment finds the bot's review status comment by its hidden marker, regardless of
+// its age or wording.  Unmarked comments from earlier versions are found by their known first lines
+// instead, so they are adopted rather than duplicated.  Any other review status comments are superseded.
+func (a *App) findReviewStatusComment() (*github.IssueComment, []*github.IssueComment, error) {
+	markerPrefix := commentMarkerPrefix(commentKindReviewStatus)
+	prefixes := append([]string{markerPrefix}, legacyReviewStatusPrefixes...)
+	comments, err := a.client.FindOwnComments(prefixes)
+	if err != nil || len(comments) == 0 {
+		return nil, nil, err
+	}
+
+	existing := comments[0]
+	marked := f.Filtered(comments, func(c *github.IssueComment) bool { return strings.HasPrefix(c.GetBody(), markerPrefix) })
+	if len(marked) > 0 {
+		existing = marked[0]
+	} else {
+		a.printDebug("Adopting legacy review status comment\n")
+	}
+	superseded := f.Filtered(comments, func(c *github.IssueComment) bool { return c != existing })
+	return existing, superseded, nil
+}
+
 func (a *App) addOptionalCcComment(allOptionalReviewerNames []string) error {
 	// Add CC comment to the PR with the optional reviewers that have not already been mentioned in the PR comments
 
-	if a.config.Quiet || len(allOptionalReviewerNames) == 0 {
+	if a.config.Quiet {
+		return nil
+	}
+
+	if a.foldCc() || a.removesOutdatedComments() {
+		ccComments, err := a.client.FindOwnComments([]string{commentMarkerPrefix(commentKindCc)})
+		if err != nil {
+			return fmt.Errorf("FindComments Error: %v", err)
+		}
+		// cc comments are outdated once none of the reviewers they mention are optional reviewers,
+		// or all of them when the optional reviewers are listed in the review status comment
+		a.removeOutdatedComments(f.Filtered(ccComments, func(c *github.IssueComment) bool {
+			return a.foldCc() || !slices.ContainsFunc(allOptionalReviewerNames, func(name string) bool {
+				return strings.Contains(c.GetBody(), name)
+			})
+		}))
+	}
+	if a.foldCc() || len(allOptionalReviewerNames) == 0 {
 		return nil
 	}
 
@@ -468,9 +804,13 @@ func (a *App) addOptionalCcComment(allOptionalReviewerNames []string) error {
 
 	// Add the CC comment if there are any viewers to ping
 	if len(viewersToPing) > 0 {
-		comment := fmt.Sprintf("cc %s", strings.Join(viewersToPing, " "))
+		comment, err := a.renderComment(a.templates.cc, defaultCc, CcData{Reviewers: viewersToPing})
+		if err != nil {
+			return fmt.Errorf("failed to render cc comment: %w", err)
+		}
+		comment = withCommentMarker(commentKindCc, comment)
 		a.printDebug("Adding CC comment: %q\n", comment)
-		err := a.client.AddComment(comment)
+		err = a.client.AddComment(comment)
 		if err != nil {
 			return fmt.Errorf("AddComment Error: %v", err)
 		}
@@ -519,9 +859,15 @@ func (a *App) processApprovals(ghApprovals []*gh.CurrentApproval) (int, error) {
 
 	a.codeowners.ApplyApprovals(approvers)
 
-	if len(approvalsToDismiss) > 0 {
-		a.printDebug("Dismissing Stale Approvals: %+v\n", approvalsToDismiss)
-		if err := a.client.DismissStaleReviews(approvalsToDismiss); err != nil {
+	// Comment approvals have no review to dismiss, a stale one just stops counting
+	staleCommentApprovals := f.Filtered(approvalsToDismiss, func(approval *gh.CurrentApproval) bool { return approval.CommentID != 0 })
+	if len(staleCommentApprovals) > 0 {
+		a.printDebug("Ignoring Stale Comment Approvals: %+v\n", staleCommentApprovals)
+	}
+	reviewsToDismiss := f.Filtered(approvalsToDismiss, func(approval *gh.CurrentApproval) bool { return approval.CommentID == 0 })
+	if len(reviewsToDismiss) > 0 {
+		a.printDebug("Dismissing Stale Approvals: %+v\n", reviewsToDismiss)
+		if err := a.client.DismissStaleReviews(reviewsToDismiss); err != nil {
 			return 0, fmt.Errorf("DismissStaleReviews Error: %v", err)
 		}
 	}
@@ -529,6 +875,23 @@ func (a *App) processApprovals(ghApprovals []*gh.CurrentApproval) (int, error) {
 	return len(ghApprovals) - len(approvalsToDismiss), nil
 }
 
+// withCommentApprovals adds the `/approve` comment approvals of owners without an approving review
+func (a *App) withCommentApprovals(ghApprovals []*gh.CurrentApproval) ([]*gh.CurrentApproval, error) {
+	commentApprovals, err := a.client.GetCommentApprovals()
+	if err != nil {
+		return nil, fmt.Errorf("GetCommentApprovals Error: %v", err)
+	}
+	for _, commentApproval := range commentApprovals {
+		hasReview := slices.ContainsFunc(ghApprovals, func(approval *gh.CurrentApproval) bool {
+			return approval.GHLogin.Equals(commentApproval.GHLogin)
+		})
+		if !hasReview {
+			ghApprovals = append(ghApprovals, commentApproval)
+		}
+	}
+	return ghApprovals, nil
+}
+
 func (a *App) requestReviews() error {
 	if a.config.Quiet {
 		return nil
@@ -550,11 +913,41 @@ func (a *App) requestReviews() error {
 	}
 	a.printDebug("Already Reviewed Owners: %s\n", codeowners.OriginalStrings(previousReviewers))
 
-	filteredOwners := unapprovedOwners.FilterOut(currentlyRequestedOwners...)
-	filteredOwners = filteredOwners.FilterOut(previousReviewers...)
-	filteredOwnerNames := filteredOwners.Flatten()
+	load, err := a.reviewLoad()
+	if err != nil {
+		return err
+	}
+	requestableOwners := unapprovedOwners
+	if a.Conf.ReviewRequests != nil && a.Conf.ReviewRequests.MaxOpenReviewsPerUser > 0 {
+		requestableOwners = a.skipOverloaded(unapprovedOwners, currentlyRequestedOwners, load)
+	}
 
-	if len(filteredOwners) > 0 {
+	var filteredOwnerNames []codeowners.Slug
+	if a.Conf.ReviewRequests != nil && a.Conf.ReviewRequests.Minimal {
+		// Cover every unapproved group with as few reviewers as possible, only requesting new ones
+		minimalReviewers := requestableOwners.FilterOut(previousReviewers...).MinimalCover(a.reviewerRank(currentlyRequestedOwners))
+		a.printDebug("Minimal Reviewer Set: %s\n", codeowners.OriginalStrings(minimalReviewers))
+		filteredOwnerNames = codeowners.FilterOutNames(minimalReviewers, currentlyRequestedOwners)
+	} else {
+		filteredOwners := requestableOwners.FilterOut(currentlyRequestedOwners...)
+		filteredOwners = filteredOwners.FilterOut(previousReviewers...)
+		filteredOwnerNames = filteredOwners.Flatten()
+	}
+
+	if len(a.unavailable) > 0 {
+		// Unavailable owners are not requested; their backups are part of their groups instead
+		unavailableOwnerNames := f.Filtered(filteredOwnerNames, a.isUnavailable)
+		if len(unavailableOwnerNames) > 0 {
+			a.printDebug("Skipping Unavailable Reviewers: %s\n", codeowners.OriginalStrings(unavailableOwnerNames))
+			filteredOwnerNames = codeowners.FilterOutNames(filteredOwnerNames, unavailableOwnerNames)
+		}
+	}
+
+	if a.Conf.ReviewRequests != nil && a.Conf.ReviewRequests.AssignIndividuals && len(filteredOwnerNames) > 0 {
+		filteredOwnerNames = a.assignIndividuals(filteredOwnerNames, load)
+	}
+
+	if len(filteredOwnerNames) > 0 {
 		a.printDebug("Requesting Reviews from: %s\n", codeowners.OriginalStrings(filteredOwnerNames))
 		if err := a.client.RequestReviewers(codeowners.OriginalStrings(filteredOwnerNames)); err != nil {
 			return fmt.Errorf("RequestReviewers Error: %v", err)
@@ -564,6 +957,109 @@ func (a *App) requestReviews() error {
 	return nil
 }
 
+// reviewerRank ranks reviewers by the review_requests.prefer preferences (lower is preferred).
+// Earlier preferences outweigh later ones.
+func (a *App) reviewerRank(currentlyRequested []codeowners.Slug) func(codeowners.Slug) int {
+	prefer := a.Conf.ReviewRequests.Prefer
+	for _, preference := range prefer {
+		switch preference {
+		case owners.PreferCurrentlyRequested, owners.PreferIndividuals, owners.PreferTeams:
+		default:
+			a.printWarn("WARNING: Unknown review_requests.prefer value %q\n", preference)
+		}
+	}
+	return func(name codeowners.Slug) int {
+		rank := 0
+		for _, preference := range prefer {
+			var matches bool
+			switch preference {
+			case owners.PreferCurrentlyRequested:
+				matches = codeowners.ContainsSlug(currentlyRequested, name)
+			case owners.PreferIndividuals:
+				matches = !name.IsTeam()
+			case owners.PreferTeams:
+				matches = name.IsTeam()
+			}
+			rank <<= 1
+			if !matches {
+				rank |= 1
+			}
+		}
+		return rank
+	}
+}
+
+const defaultCheckRunName = "Codeowners Plus"
+
+// reportCheckRun publishes the evaluation result as a check run, with an annotation for
+// every file still waiting on an owner's approval and every unowned file
+func (a *App) reportCheckRun(outputData *OutputData) error {
+	name := a.Conf.Enforcement.CheckRunName
+	if name == "" {
+		name = defaultCheckRunName
+	}
+
+	// Annotate the first changed line of each file
+	firstChangedLine := make(map[string]int)
+	for _, file := range a.gitDiff.AllChanges() {
+		if len(file.Hunks) > 0 {
+			firstChangedLine[file.FileName] = file.Hunks[0].Start
+		}
+	}
+
+	result := gh.CheckRunResult{
+		Success: outputData.Success,
+		Title:   "Codeowners reviews required",
+		Summary: outputData.Message,
+	}
+	if outputData.Success {
+		result.Title = "Codeowners reviews satisfied"
+	}
+
+	text := strings.Builder{}
+	unapprovedOwners := a.codeowners.AllRequired()
+	if !outputData.Success && len(unapprovedOwners) > 0 {
+		// builder.WriteString error return is always nil
+		_, _ = fmt.Fprintf(&text, "### Still required\n\n%s\n", unapprovedOwners.ToCommentString(false))
+
+		fileRequired := a.codeowners.FileRequired()
+		files := slices.Sorted(maps.Keys(fileRequired))
+		for _, file := range files {
+			unapproved := codeowners.ReviewerGroups(f.Filtered(fileRequired[file], func(rg *codeowners.ReviewerGroup) bool {
+				return !rg.Approved
+			}))
+			if len(unapproved) == 0 {
+				continue
+			}
+			result.Annotations = append(result.Annotations, gh.CheckRunAnnotation{
+				Path:    file,
+				Line:    firstChangedLine[file],
+				Level:   gh.AnnotationLevelFailure,
+				Title:   "Codeowners approval required",
+				Message: fmt.Sprintf("Requires approval from:\n%s", unapproved.ToCommentString(false)),
+			})
+		}
+	}
+
+	if !a.Conf.SuppressUnownedWarning && len(outputData.UnownedFiles) > 0 {
+		_, _ = fmt.Fprintf(&text, "\n### Unowned files\n\n")
+		for _, file := range outputData.UnownedFiles {
+			_, _ = fmt.Fprintf(&text, "- `%s`\n", file)
+			result.Annotations = append(result.Annotations, gh.CheckRunAnnotation{
+				Path:    file,
+				Line:    firstChangedLine[file],
+				Level:   gh.AnnotationLevelWarning,
+				Title:   "Unowned file",
+				Message: "No code owner is defined for this file",
+			})
+		}
+	}
+	result.Text = text.String()
+
+	a.printDebug("Creating check run %q with %d annotation(s)\n", name, len(result.Annotations))
+	return a.client.CreateCheckRun(name, result)
+}
+
 func (a *App) printFileOwners(codeOwners codeowners.CodeOwners) {
 	a.printDebug("File Reviewers:\n")
 	a.printDebug("%s", a.getFileOwnersMapToString(codeOwners.FileRequired()))
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	owners "github.com/multimediallc/codeowners-plus/internal/config"
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+	f "github.com/multimediallc/codeowners-plus/pkg/functional"
+)
+
+// assignIndividuals replaces every team in reviewers with a single member of the team, picked by
+// the review_requests.assignment strategy.  A team is kept as is when none of its members can be
+// picked, and dropped when one of the other requested reviewers is already a member.  load holds
+// the open review requests per user, when counted.
+func (a *App) assignIndividuals(reviewers []codeowners.Slug, load map[string]int) []codeowners.Slug {
+	reviewRequests := a.Conf.ReviewRequests
+
+	excluded := f.NewSet[string]()
+	for _, user := range reviewRequests.Exclude {
+		excluded.Add(normalizeLogin(user))
+	}
+	if author := a.client.PR().GetUser().GetLogin(); author != "" {
+		// GitHub rejects review requests for the PR author
+		excluded.Add(normalizeLogin(author))
+	}
+	if limit := reviewRequests.MaxOpenReviewsPerUser; limit > 0 {
+		for user, openReviews := range load {
+			if openReviews >= limit {
+				excluded.Add(user)
+			}
+		}
+	}
+
+	pickLoad := load
+	switch reviewRequests.Assignment {
+	case owners.AssignmentLeastLoaded:
+	case owners.AssignmentRoundRobin:
+		pickLoad = nil
+	default:
+		a.printWarn("WARNING: Unknown review_requests.assignment value %q, using %q\n", reviewRequests.Assignment, owners.AssignmentRoundRobin)
+		pickLoad = nil
+	}
+
+	assigned := f.Filtered(reviewers, func(reviewer codeowners.Slug) bool { return !reviewer.IsTeam() })
+	for _, team := range f.Filtered(reviewers, codeowners.Slug.IsTeam) {
+		members := a.client.TeamMembers(team.Original())
+		if slices.ContainsFunc(assigned, func(reviewer codeowners.Slug) bool {
+			return !reviewer.IsTeam() && slices.Contains(members, normalizeLogin(reviewer.Original()))
+		}) {
+			a.printDebug("Team %s is covered by an assigned member\n", team.Original())
+			continue
+		}
+		candidates := f.Filtered(members, func(member string) bool {
+			return !excluded.Contains(member) && !a.unavailable.Contains(member)
+		})
+		if len(candidates) == 0 {
+			a.printWarn("WARNING: No team members of %s can be assigned, requesting the team\n", team.Original())
+			assigned = append(assigned, team)
+			continue
+		}
+		member := pickTeamMember(candidates, pickLoad, a.config.PR)
+		a.printDebug("Assigning %s for %s\n", member, team.Original())
+		assigned = append(assigned, codeowners.NewSlug("@"+member))
+		if load != nil {
+			load[member]++
+		}
+	}
+	return assigned
+}
+
+// reviewLoad counts the open review requests per user, when review_requests needs them
+func (a *App) reviewLoad() (map[string]int, error) {
+	reviewRequests := a.Conf.ReviewRequests
+	if reviewRequests == nil {
+		return nil, nil
+	}
+	leastLoaded := reviewRequests.AssignIndividuals && reviewRequests.Assignment == owners.AssignmentLeastLoaded
+	if reviewRequests.MaxOpenReviewsPerUser <= 0 && !leastLoaded {
+		return nil, nil
+	}
+	load, err := a.client.OpenReviewRequestCounts()
+	if err != nil {
+		return nil, fmt.Errorf("OpenReviewRequestCounts Error: %v", err)
+	}
+	return load, nil
+}
+
+// skipOverloaded removes individuals at the review_requests.max_open_reviews_per_user limit from
+// the groups reviews are requested from, as long as another member of the group remains.  The
+// skipped individuals are noted in the review status comment.
+func (a *App) skipOverloaded(groups codeowners.ReviewerGroups, currentlyRequested []codeowners.Slug, load map[string]int) codeowners.ReviewerGroups {
+	limit := a.Conf.ReviewRequests.MaxOpenReviewsPerUser
+	overloaded := func(name codeowners.Slug) bool {
+		return !name.IsTeam() && !codeowners.ContainsSlug(currentlyRequested, name) && load[normalizeLogin(name.Original())] >= limit
+	}
+
+	requestable := make(codeowners.ReviewerGroups, 0, len(groups))
+	for _, group := range groups {
+		names := f.Filtered(group.Names, func(name codeowners.Slug) bool { return !overloaded(name) })
+		if len(names) == 0 || len(names) == len(group.Names) {
+			requestable = append(requestable, group)
+			continue
+		}
+		requestable = append(requestable, &codeowners.ReviewerGroup{Names: names, Approved: group.Approved})
+	}
+
+	// Individuals still requested for a group they own alone are not skipped
+	a.skippedReviewers = codeowners.FilterOutNames(f.Filtered(groups.Flatten(), overloaded), requestable.Flatten())
+	if len(a.skippedReviewers) > 0 {
+		a.printDebug("Skipping Overloaded Reviewers: %s\n", codeowners.OriginalStrings(a.skippedReviewers))
+	}
+	return requestable
+}
+
+// pickTeamMember picks from the sorted candidates in round-robin order by PR number.  When load
+// is set, the candidate with the fewest open review requests is picked instead, with ties broken
+// in round-robin order.
+func pickTeamMember(candidates []string, load map[string]int, pr int) string {
+	start := pr % len(candidates)
+	picked := candidates[start]
+	if load == nil {
+		return picked
+	}
+	for i := 1; i < len(candidates); i++ {
+		candidate := candidates[(start+i)%len(candidates)]
+		if load[candidate] < load[picked] {
+			picked = candidate
+		}
+	}
+	return picked
+}
+
+func normalizeLogin(login string) string {
+	return strings.ToLower(strings.TrimPrefix(login, "@"))
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	owners "github.com/multimediallc/codeowners-plus/internal/config"
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+	f "github.com/multimediallc/codeowners-plus/pkg/functional"
+)
+
+// backupOwners returns the configured availability.backups keyed by normalized owner
+func backupOwners(availability *owners.Availability) map[string][]codeowners.Slug {
+	backups := make(map[string][]codeowners.Slug)
+	if availability == nil {
+		return backups
+	}
+	for owner, names := range availability.Backups {
+		owner := codeowners.NewSlug(owner).Normalized()
+		backups[owner] = append(backups[owner], codeowners.NewSlugs(names)...)
+	}
+	return backups
+}
+
+// applyAvailability looks up which required owners and team members are unavailable, and adds
+// the backups of unavailable owners to their groups.  Unavailable users are remembered so they
+// are skipped when requesting reviews.
+func (a *App) applyAvailability(availability *owners.Availability, fileReader codeowners.FileReader) error {
+	if availability == nil || (availability.File == "" && !availability.GitHubStatus) {
+		return nil
+	}
+
+	// Every individual who may be requested: owners, team members and backups
+	requiredOwners := a.codeowners.AllRequired().Flatten()
+	backups := backupOwners(availability)
+	names := slices.Clone(requiredOwners)
+	for _, ownerBackups := range backups {
+		names = append(names, ownerBackups...)
+	}
+	logins := f.NewSet[string]()
+	for _, owner := range names {
+		if owner.IsTeam() {
+			for _, member := range a.client.TeamMembers(owner.Original()) {
+				logins.Add(member)
+			}
+		} else {
+			logins.Add(normalizeLogin(owner.Original()))
+		}
+	}
+	candidates := logins.Items()
+	slices.Sort(candidates)
+
+	unavailable := f.NewSet[string]()
+	if availability.File != "" {
+		absences, err := owners.ReadAvailabilityFile(a.config.RepoDir, availability.File, fileReader)
+		if err != nil {
+			a.printWarn("WARNING: Error reading availability file %s: %v\n", availability.File, err)
+		} else {
+			now := time.Now()
+			for _, login := range candidates {
+				if absences.IsUnavailable(login, now) {
+					unavailable.Add(login)
+				}
+			}
+		}
+	}
+	if availability.GitHubStatus && len(candidates) > 0 {
+		busy, err := a.client.BusyUsers(candidates)
+		if err != nil {
+			return fmt.Errorf("BusyUsers Error: %v", err)
+		}
+		for _, login := range busy {
+			unavailable.Add(normalizeLogin(login))
+		}
+	}
+	a.unavailable = unavailable
+	if len(unavailable) == 0 {
+		return nil
+	}
+	a.printDebug("Unavailable Users: %s\n", slices.Sorted(slices.Values(unavailable.Items())))
+
+	for _, owner := range requiredOwners {
+		if !a.isUnavailable(owner) {
+			continue
+		}
+		ownerBackups := f.Filtered(backups[owner.Normalized()], func(backup codeowners.Slug) bool {
+			return !a.isUnavailable(backup)
+		})
+		if len(ownerBackups) == 0 {
+			a.printWarn("WARNING: %s is unavailable and has no available backup\n", owner.Original())
+			continue
+		}
+		a.printDebug("Adding backups %s for unavailable %s\n", codeowners.OriginalStrings(ownerBackups), owner.Original())
+		a.codeowners.AddBackups(owner, ownerBackups)
+	}
+	return nil
+}
+
+// isUnavailable reports whether a user is unavailable, or every member of a team is
+func (a *App) isUnavailable(owner codeowners.Slug) bool {
+	if len(a.unavailable) == 0 {
+		return false
+	}
+	if !owner.IsTeam() {
+		return a.unavailable.Contains(normalizeLogin(owner.Original()))
+	}
+	members := a.client.TeamMembers(owner.Original())
+	return len(members) > 0 && !slices.ContainsFunc(members, func(member string) bool {
+		return !a.unavailable.Contains(member)
+	})
+}
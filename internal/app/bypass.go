@@ -0,0 +1,155 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	gh "github.com/multimediallc/codeowners-plus/internal/github"
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+	f "github.com/multimediallc/codeowners-plus/pkg/functional"
+)
+
+// BypassAuditEntry is a line of the bypass audit log
+type BypassAuditEntry struct {
+	Time    time.Time `json:"time"`
+	Repo    string    `json:"repo"`
+	PR      int       `json:"pr"`
+	HeadSHA string    `json:"head_sha"`
+	*gh.Bypass
+	// Success is the result of the evaluation the bypass was applied to
+	Success bool `json:"success"`
+}
+
+// findBypasses collects the bypasses from reviews, bypass labels and `/codeowners bypass` comments.  A full bypass
+// makes scoped ones irrelevant, so it is returned alone.
+func (a *App) findBypasses() ([]*gh.Bypass, error) {
+	var allowedBypassUsers, bypassLabels []string
+	// Admins may bypass even while admin bypass is disabled, so a reason is required unless opted out
+	requireReason := a.Conf.AdminBypass == nil || a.Conf.AdminBypass.RequireReason
+	if a.Conf.AdminBypass != nil && a.Conf.AdminBypass.Enabled {
+		allowedBypassUsers = a.Conf.AdminBypass.AllowedUsers
+		bypassLabels = a.Conf.AdminBypass.Labels
+	}
+	reviewBypasses, err := a.client.FindBypassApprovals(allowedBypassUsers, bypassLabels)
+	if err != nil {
+		return nil, fmt.Errorf("FindBypassApprovals Error: %v", err)
+	}
+	commandBypasses, err := a.bypassCommands()
+	if err != nil {
+		return nil, err
+	}
+
+	bypasses := make([]*gh.Bypass, 0)
+	for _, bypass := range slices.Concat(reviewBypasses, commandBypasses) {
+		if requireReason && bypass.Reason == "" {
+			a.printWarn("WARNING: Ignoring bypass by %s without a reason\n", bypass.User)
+			continue
+		}
+		bypasses = append(bypasses, bypass)
+	}
+	if full, found := f.Find(bypasses, isFullBypass); found {
+		return []*gh.Bypass{full}, nil
+	}
+	return bypasses, nil
+}
+
+// isFullBypass reports whether the bypass covers the whole PR
+func isFullBypass(bypass *gh.Bypass) bool {
+	return len(bypass.Scope) == 0
+}
+
+// applyBypassScopes satisfies the ownership groups covered by scoped bypasses: groups with an owner
+// named in a scope, and groups whose changed files are all matched by the paths of the scopes
+func (a *App) applyBypassScopes(bypasses []*gh.Bypass) {
+	scope := f.RemoveDuplicates(slices.Concat(f.Map(bypasses, func(bypass *gh.Bypass) []string { return bypass.Scope })...))
+	if len(scope) == 0 {
+		return
+	}
+	owners, paths := make([]string, 0), make([]string, 0)
+	for _, item := range scope {
+		if strings.HasPrefix(item, "@") {
+			owners = append(owners, item)
+		} else {
+			paths = append(paths, item)
+		}
+	}
+	a.printDebug("Applying Bypass Scope: owners %s, paths %s\n", owners, paths)
+	a.codeowners.ApplyApprovals(codeowners.NewSlugs(owners))
+	if len(paths) == 0 {
+		return
+	}
+
+	covered := make(map[*codeowners.ReviewerGroup]bool)
+	for file, groups := range a.codeowners.FileRequired() {
+		inScope := slices.ContainsFunc(paths, func(pattern string) bool { return bypassScopeMatches(pattern, file) })
+		for _, group := range groups {
+			previous, seen := covered[group]
+			covered[group] = inScope && (previous || !seen)
+		}
+	}
+	for group, inScope := range covered {
+		if inScope {
+			group.Approved = true
+		}
+	}
+}
+
+// bypassScopeMatches reports whether a scope path matches the file: the file itself, a directory
+// containing it, or a path.Match pattern
+func bypassScopeMatches(pattern string, file string) bool {
+	pattern = strings.TrimPrefix(pattern, "/")
+	if dir := strings.TrimSuffix(pattern, "/"); file == dir || strings.HasPrefix(file, dir+"/") {
+		return true
+	}
+	matched, err := path.Match(pattern, file)
+	return err == nil && matched
+}
+
+// bypassMessage describes the applied bypasses in the result message
+func bypassMessage(bypasses []*gh.Bypass) string {
+	lines := f.Map(bypasses, func(bypass *gh.Bypass) string {
+		line := "@" + bypass.User
+		if len(bypass.Scope) > 0 {
+			line += fmt.Sprintf(" (scope: %s)", strings.Join(bypass.Scope, ", "))
+		}
+		if bypass.Reason != "" {
+			line += ": " + bypass.Reason
+		}
+		return line
+	})
+	return strings.Join(lines, "\n")
+}
+
+// writeBypassAuditLog appends a JSON line for every applied bypass to the bypass audit log
+func (a *App) writeBypassAuditLog(success bool) error {
+	if a.config.BypassAuditLog == "" || len(a.bypasses) == 0 {
+		return nil
+	}
+	file, err := os.OpenFile(a.config.BypassAuditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open bypass audit log: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	encoder := json.NewEncoder(file)
+	for _, bypass := range a.bypasses {
+		entry := BypassAuditEntry{
+			Time:    time.Now().UTC(),
+			Repo:    a.config.Repo,
+			PR:      a.config.PR,
+			HeadSHA: a.client.PR().GetHead().GetSHA(),
+			Bypass:  bypass,
+			Success: success,
+		}
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("failed to write bypass audit log: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v89/github"
+	owners "github.com/multimediallc/codeowners-plus/internal/config"
+	gh "github.com/multimediallc/codeowners-plus/internal/github"
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+)
+
+func TestFindBypasses(t *testing.T) {
+	scoped := &gh.Bypass{User: "admin", Reason: "typo", Scope: []string{"docs/"}, Source: gh.BypassSourceReview}
+	unexplained := &gh.Bypass{User: "admin", Source: gh.BypassSourceReview}
+
+	tt := []struct {
+		name            string
+		bypasses        []*gh.Bypass
+		comments        []*github.IssueComment
+		allowNoReason   bool
+		expected        []string
+		expectedWarning bool
+	}{
+		{
+			name:     "scoped bypasses",
+			bypasses: []*gh.Bypass{scoped},
+			comments: []*github.IssueComment{
+				{Body: github.Ptr("/codeowners bypass scope=@org/docs release"), User: &github.User{Login: github.Ptr("repo-admin")}},
+			},
+			expected: []string{"admin:typo:docs/", "repo-admin:release:@org/docs"},
+		},
+		{
+			name:     "full bypass supersedes scoped bypasses",
+			bypasses: []*gh.Bypass{scoped},
+			comments: []*github.IssueComment{
+				{Body: github.Ptr("/codeowners bypass outage"), User: &github.User{Login: github.Ptr("repo-admin")}},
+			},
+			expected: []string{"repo-admin:outage:"},
+		},
+		{
+			name:            "reason required by default",
+			bypasses:        []*gh.Bypass{unexplained, scoped},
+			expected:        []string{"admin:typo:docs/"},
+			expectedWarning: true,
+		},
+		{
+			name:          "bypass without reason when not required",
+			bypasses:      []*gh.Bypass{unexplained},
+			allowNoReason: true,
+			expected:      []string{"admin::"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			app, mockClient := setupAppForTest(t, false)
+			warnings := &bytes.Buffer{}
+			app.config.WarningBuffer = warnings
+			app.Conf.AdminBypass = &owners.AdminBypass{Enabled: true, RequireReason: !tc.allowNoReason}
+			mockClient.bypasses = tc.bypasses
+			mockClient.comments = tc.comments
+
+			bypasses, err := app.findBypasses()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := make([]string, 0, len(bypasses))
+			for _, bypass := range bypasses {
+				got = append(got, bypass.User+":"+bypass.Reason+":"+strings.Join(bypass.Scope, ","))
+			}
+			if strings.Join(got, " ") != strings.Join(tc.expected, " ") {
+				t.Errorf("expected bypasses %v, got %v", tc.expected, got)
+			}
+			if (warnings.Len() > 0) != tc.expectedWarning {
+				t.Errorf("expected warning %t, got %q", tc.expectedWarning, warnings.String())
+			}
+		})
+	}
+}
+
+func TestFindBypassesLabels(t *testing.T) {
+	app, mockClient := setupAppForTest(t, false)
+	app.Conf.AdminBypass = &owners.AdminBypass{Enabled: false, Labels: []string{"emergency"}}
+	if _, err := app.findBypasses(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mockClient.BypassLabelsInput != nil {
+		t.Errorf("expected no bypass labels while admin bypass is disabled, got %v", mockClient.BypassLabelsInput)
+	}
+
+	app.Conf.AdminBypass.Enabled = true
+	if _, err := app.findBypasses(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(mockClient.BypassLabelsInput, []string{"emergency"}) {
+		t.Errorf("expected bypass labels [emergency], got %v", mockClient.BypassLabelsInput)
+	}
+}
+
+func TestApplyBypassScopes(t *testing.T) {
+	docs := &codeowners.ReviewerGroup{Names: codeowners.NewSlugs([]string{"@org/docs"})}
+	shared := &codeowners.ReviewerGroup{Names: codeowners.NewSlugs([]string{"@org/writers"})}
+	backend := &codeowners.ReviewerGroup{Names: codeowners.NewSlugs([]string{"@org/backend"})}
+	mockOwners := &mockCodeOwners{fileRequiredMap: map[string]codeowners.ReviewerGroups{
+		"docs/guide.md":  {docs, shared},
+		"docs/api/v1.md": {docs},
+		"README.md":      {shared},
+		"main.go":        {backend},
+	}}
+	app, _ := setupAppForTest(t, false)
+	app.codeowners = mockOwners
+
+	app.applyBypassScopes([]*gh.Bypass{
+		{User: "admin", Scope: []string{"docs/"}},
+		{User: "admin", Scope: []string{"@org/backend", "docs/"}},
+	})
+
+	if !docs.Approved {
+		t.Error("expected the group owning only files in scope to be satisfied")
+	}
+	if shared.Approved {
+		t.Error("expected the group owning files out of scope to stay required")
+	}
+	if len(mockOwners.appliedApprovals) != 1 || !mockOwners.appliedApprovals[0].EqualsString("@org/backend") {
+		t.Errorf("expected the owner in scope to be approved, got %v", mockOwners.appliedApprovals)
+	}
+}
+
+func TestBypassScopeMatches(t *testing.T) {
+	tt := []struct {
+		name     string
+		pattern  string
+		file     string
+		expected bool
+	}{
+		{name: "file", pattern: "README.md", file: "README.md", expected: true},
+		{name: "directory", pattern: "docs/", file: "docs/api/v1.md", expected: true},
+		{name: "directory without slash", pattern: "/docs", file: "docs/guide.md", expected: true},
+		{name: "directory prefix", pattern: "docs", file: "docsite/index.md", expected: false},
+		{name: "pattern", pattern: "*.md", file: "CHANGELOG.md", expected: true},
+		{name: "pattern in directory", pattern: "*.md", file: "docs/guide.md", expected: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := bypassScopeMatches(tc.pattern, tc.file); got != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestWriteBypassAuditLog(t *testing.T) {
+	auditLog := filepath.Join(t.TempDir(), "bypasses.jsonl")
+	app, mockClient := setupAppForTest(t, false)
+	app.config.Repo = "org/repo"
+	app.config.PR = 7
+	app.config.BypassAuditLog = auditLog
+	mockClient.pr = &github.PullRequest{Head: &github.PullRequestBranch{SHA: github.Ptr("abc123")}}
+	app.bypasses = []*gh.Bypass{{User: "admin", Reason: "outage", Source: gh.BypassSourceComment}}
+
+	for range 2 {
+		if err := app.writeBypassAuditLog(true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(auditLog)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 appended lines, got %d: %s", len(lines), data)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("failed to parse audit log line: %v", err)
+	}
+	for key, expected := range map[string]any{"repo": "org/repo", "pr": float64(7), "head_sha": "abc123", "user": "admin", "reason": "outage", "source": "comment", "success": true} {
+		if entry[key] != expected {
+			t.Errorf("expected %s %v, got %v", key, expected, entry[key])
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v89/github"
+	gh "github.com/multimediallc/codeowners-plus/internal/github"
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+	f "github.com/multimediallc/codeowners-plus/pkg/functional"
+)
+
+// IssueCommentEventName is the GitHub Actions event triggered when a PR is commented on
+const IssueCommentEventName = "issue_comment"
+
+// CommandPrefix starts a slash command on the first line of a PR comment
+const CommandPrefix = "/codeowners"
+
+// Slash commands
+const (
+	CommandExplain = "explain"
+	CommandRecheck = "recheck"
+	CommandBypass  = "bypass"
+	CommandRequest = "request"
+	// CommandApprove is set for `/approve` and `/approve cancel` comments (with a "cancel" argument),
+	// which record the PR head and evaluate the PR again
+	CommandApprove = "approve"
+)
+
+const commandUsage = "Usage:\n" +
+	"- `/codeowners explain <path>` shows the owners of a file\n" +
+	"- `/codeowners recheck` re-evaluates the codeowners reviews\n" +
+	"- `/codeowners bypass [scope=<path or owner>,...] <reason>` bypasses the codeowners reviews, or only those in scope (repository admins and `admin_bypass.allowed_users`)\n" +
+	"- `/codeowners request @user` requests a review (the PR author and repository admins)"
+
+// Command is a slash command from a PR comment
+type Command struct {
+	PR   int
+	Name string
+	Args []string
+	// Text is the command line, quoted in the reply
+	Text      string
+	Author    string
+	CommentID int64
+}
+
+// ParseCommand parses the slash command on the first line of a comment, or returns nil when the
+// comment is not a command
+func ParseCommand(body string) *Command {
+	line, _, _ := strings.Cut(strings.TrimSpace(body), "\n")
+	line = strings.TrimSpace(line)
+	fields := strings.Fields(line)
+	if len(fields) == 0 || fields[0] != CommandPrefix {
+		return nil
+	}
+	command := &Command{Text: line, Args: []string{}}
+	if len(fields) > 1 {
+		command.Name = strings.ToLower(fields[1])
+		command.Args = fields[2:]
+	}
+	return command
+}
+
+// ReadIssueCommentEvent reads the issue_comment webhook payload at eventPath (GITHUB_EVENT_PATH).
+// Returns nil when the comment is not a new slash command on a PR.
+func ReadIssueCommentEvent(eventPath string) (*Command, error) {
+	data, err := os.ReadFile(eventPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading issue_comment event: %w", err)
+	}
+	var event github.IssueCommentEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("error parsing issue_comment event: %w", err)
+	}
+	return NewCommand(&event), nil
+}
+
+// NewCommand returns the slash command of a new PR comment, or nil when there is none
+func NewCommand(event *github.IssueCommentEvent) *Command {
+	if event.GetAction() != "created" || !event.GetIssue().IsPullRequest() {
+		return nil
+	}
+	body := event.GetComment().GetBody()
+	command := ParseCommand(body)
+	if command == nil {
+		approve, found := gh.ParseApproveCommand(body)
+		if !found {
+			return nil
+		}
+		command = &Command{Name: CommandApprove, Args: []string{}, Text: gh.ApproveCommand}
+		if !approve {
+			command.Args = []string{"cancel"}
+			command.Text += " cancel"
+		}
+	}
+	command.PR = event.GetIssue().GetNumber()
+	command.Author = event.GetComment().GetUser().GetLogin()
+	command.CommentID = event.GetComment().GetID()
+	return command
+}
+
+// runCommand handles the slash command which triggered the run.  Returns whether the PR should be
+// evaluated afterwards, which only recheck and accepted bypass commands need.
+func (a *App) runCommand(command *Command, fileReader codeowners.FileReader) (bool, error) {
+	a.printDebug("Command from @%s: %s\n", command.Author, command.Text)
+	switch command.Name {
+	case CommandRecheck:
+		return true, nil
+	case CommandApprove:
+		return true, a.recordCommentApproval(command)
+	case CommandExplain:
+		if len(command.Args) != 1 {
+			return false, a.replyToCommand(command, commandUsage)
+		}
+		explanation, err := a.explainOwners(command.Args[0], fileReader)
+		if err != nil {
+			return false, err
+		}
+		return false, a.replyToCommand(command, explanation)
+	case CommandBypass:
+		reason, scope := gh.ParseBypassArgs(command.Args)
+		if reason == "" {
+			return false, a.replyToCommand(command, "A reason is required to bypass codeowners reviews: `/codeowners bypass [scope=<path or owner>,...] <reason>`")
+		}
+		allowed, err := a.canBypass(command.Author)
+		if err != nil {
+			return false, err
+		}
+		if !allowed {
+			return false, a.replyToCommand(command, fmt.Sprintf("@%s is not allowed to bypass codeowners reviews.", command.Author))
+		}
+		bypass := &gh.Bypass{User: command.Author, Reason: reason, Scope: scope}
+		return true, a.replyToCommand(command, "🔓 Codeowners reviews bypassed by "+bypassMessage([]*gh.Bypass{bypass}))
+	case CommandRequest:
+		reviewers := f.Map(command.Args, func(reviewer string) string { return "@" + strings.TrimPrefix(reviewer, "@") })
+		if len(reviewers) == 0 {
+			return false, a.replyToCommand(command, commandUsage)
+		}
+		allowed, err := a.canRequestReviews(command.Author)
+		if err != nil {
+			return false, err
+		}
+		if !allowed {
+			return false, a.replyToCommand(command, fmt.Sprintf("@%s is not allowed to request reviews, only the PR author and repository admins are.", command.Author))
+		}
+		if a.config.Quiet {
+			a.printDebug("Skipping review request of %s (quiet mode)\n", reviewers)
+			return false, nil
+		}
+		if err := a.client.RequestReviewers(reviewers); err != nil {
+			return false, fmt.Errorf("RequestReviewers Error: %v", err)
+		}
+		return false, a.replyToCommand(command, fmt.Sprintf("Requested a review from %s.", strings.Join(reviewers, " ")))
+	default:
+		return false, a.replyToCommand(command, commandUsage)
+	}
+}
+
+// explainOwners describes the required and optional owners of a path, according to the
+// .codeowners files of the base branch
+func (a *App) explainOwners(path string, fileReader codeowners.FileReader) (string, error) {
+	path = strings.TrimPrefix(path, "/")
+	pathOwners, err := codeowners.New(a.config.RepoDir, []codeowners.DiffFile{{FileName: path}}, fileReader, a.config.WarningBuffer)
+	if err != nil {
+		return "", fmt.Errorf("NewCodeOwners Error: %v", err)
+	}
+	required := pathOwners.FileRequired()[path]
+	optional := pathOwners.FileOptional()[path].Flatten()
+
+	var explanation strings.Builder
+	if len(required) == 0 {
+		fmt.Fprintf(&explanation, "No codeowners approval is required for `%s`.", path)
+	} else {
+		fmt.Fprintf(&explanation, "Codeowners approval required for `%s`:", path)
+		for _, group := range reviewStatusGroups(required, nil) {
+			fmt.Fprintf(&explanation, "\n- %s", strings.Join(group.Owners, " or "))
+		}
+	}
+	if len(optional) > 0 {
+		fmt.Fprintf(&explanation, "\n\nOptional reviewers: %s", strings.Join(codeowners.OriginalStrings(optional), " "))
+	}
+	return explanation.String(), nil
+}
+
+// canBypass reports whether the user may bypass codeowners reviews: repository admins, and the
+// admin_bypass.allowed_users when admin bypass is enabled
+func (a *App) canBypass(user string) (bool, error) {
+	if a.Conf.AdminBypass != nil && a.Conf.AdminBypass.Enabled {
+		if slices.ContainsFunc(a.Conf.AdminBypass.AllowedUsers, codeowners.NewSlug(user).EqualsString) {
+			return true, nil
+		}
+	}
+	isAdmin, err := a.client.IsRepositoryAdmin(user)
+	if err != nil {
+		return false, fmt.Errorf("IsRepositoryAdmin Error: %v", err)
+	}
+	return isAdmin, nil
+}
+
+// canRequestReviews reports whether the user may request reviews with a command: the PR author and
+// repository admins
+func (a *App) canRequestReviews(user string) (bool, error) {
+	if strings.EqualFold(user, a.client.PR().GetUser().GetLogin()) {
+		return true, nil
+	}
+	isAdmin, err := a.client.IsRepositoryAdmin(user)
+	if err != nil {
+		return false, fmt.Errorf("IsRepositoryAdmin Error: %v", err)
+	}
+	return isAdmin, nil
+}
+
+// bypassCommands returns the bypasses of `/codeowners bypass <reason>` comments from users allowed
+// to bypass the codeowners reviews
+func (a *App) bypassCommands() ([]*gh.Bypass, error) {
+	// Commands are matched like ParseCommand does, e.g. `/codeowners Bypass` or with extra whitespace
+	comments, err := a.client.FindComments([]string{CommandPrefix})
+	if err != nil {
+		return nil, fmt.Errorf("FindComments Error: %v", err)
+	}
+	bypasses := make([]*gh.Bypass, 0)
+	for _, comment := range comments {
+		command := ParseCommand(comment.GetBody())
+		if command == nil || command.Name != CommandBypass {
+			continue
+		}
+		reason, scope := gh.ParseBypassArgs(command.Args)
+		if reason == "" {
+			continue
+		}
+		allowed, err := a.canBypass(comment.GetUser().GetLogin())
+		if err != nil {
+			a.printWarn("WARNING: Could not check bypass permission of %s: %v\n", comment.GetUser().GetLogin(), err)
+			continue
+		}
+		if allowed {
+			bypasses = append(bypasses, &gh.Bypass{
+				User:      comment.GetUser().GetLogin(),
+				Reason:    reason,
+				Scope:     scope,
+				Source:    gh.BypassSourceComment,
+				URL:       comment.GetHTMLURL(),
+				CreatedAt: comment.GetCreatedAt().Time,
+			})
+		}
+	}
+	return bypasses, nil
+}
+
+// recordCommentApproval records the PR head for an `/approve` comment as a bot comment, which the
+// approval is attached to.  Commit dates are set by the commit author, so the head is recorded as
+// soon as the comment is handled rather than inferred later.
+func (a *App) recordCommentApproval(command *Command) error {
+	if !a.Conf.CommentApprovals || len(command.Args) > 0 {
+		return nil
+	}
+	sha := a.client.PR().GetHead().GetSHA()
+	if a.config.Quiet {
+		a.printDebug("Skipping the approval record of @%s on %s (quiet mode)\n", command.Author, sha)
+		return nil
+	}
+	comment := fmt.Sprintf("%s\nRecorded the approval of @%s on %s.", gh.ApprovalRecord(command.CommentID, sha), command.Author, sha)
+	if err := a.client.AddComment(comment); err != nil {
+		return fmt.Errorf("AddComment Error: %v", err)
+	}
+	return nil
+}
+
+// replyToCommand answers a command with a comment quoting it
+func (a *App) replyToCommand(command *Command, reply string) error {
+	if a.config.Quiet {
+		a.printDebug("Skipping reply to %q (quiet mode): %s\n", command.Text, reply)
+		return nil
+	}
+	comment := withCommentMarker(commentKindCommand, fmt.Sprintf("> %s\n\n%s", command.Text, reply))
+	if err := a.client.AddComment(comment); err != nil {
+		return fmt.Errorf("AddComment Error: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,358 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v89/github"
+	owners "github.com/multimediallc/codeowners-plus/internal/config"
+	gh "github.com/multimediallc/codeowners-plus/internal/github"
+)
+
+func TestParseCommand(t *testing.T) {
+	tt := []struct {
+		name     string
+		body     string
+		expected *Command
+	}{
+		{
+			name:     "command with arguments",
+			body:     "/codeowners explain src/main.go",
+			expected: &Command{Name: CommandExplain, Args: []string{"src/main.go"}, Text: "/codeowners explain src/main.go"},
+		},
+		{
+			name:     "only the first line is parsed",
+			body:     "  /codeowners Bypass hotfix for the outage\nthanks!",
+			expected: &Command{Name: CommandBypass, Args: []string{"hotfix", "for", "the", "outage"}, Text: "/codeowners Bypass hotfix for the outage"},
+		},
+		{
+			name:     "prefix without command",
+			body:     "/codeowners",
+			expected: &Command{Args: []string{}, Text: "/codeowners"},
+		},
+		{name: "regular comment", body: "LGTM /codeowners recheck"},
+		{name: "other prefix", body: "/codeownersplus recheck"},
+		{name: "bot reply", body: withCommentMarker(commentKindCommand, "> /codeowners recheck\n\nRechecked")},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			command := ParseCommand(tc.body)
+			if tc.expected == nil {
+				if command != nil {
+					t.Errorf("expected no command, got %+v", command)
+				}
+				return
+			}
+			if command == nil {
+				t.Fatal("expected a command, got nil")
+			}
+			if command.Name != tc.expected.Name || command.Text != tc.expected.Text || !slices.Equal(command.Args, tc.expected.Args) {
+				t.Errorf("expected command %+v, got %+v", tc.expected, command)
+			}
+		})
+	}
+}
+
+func TestNewCommand(t *testing.T) {
+	event := func(action string, pullRequest bool, body string) *github.IssueCommentEvent {
+		issue := &github.Issue{Number: github.Ptr(7)}
+		if pullRequest {
+			issue.PullRequestLinks = &github.PullRequestLinks{URL: github.Ptr("https://api.github.com/repos/org/repo/pulls/7")}
+		}
+		return &github.IssueCommentEvent{
+			Action: github.Ptr(action),
+			Issue:  issue,
+			Comment: &github.IssueComment{
+				ID:   github.Ptr[int64](70),
+				Body: github.Ptr(body),
+				User: &github.User{Login: github.Ptr("octocat")},
+			},
+		}
+	}
+
+	command := NewCommand(event("created", true, "/codeowners recheck"))
+	if command == nil {
+		t.Fatal("expected a command, got nil")
+	}
+	if command.PR != 7 || command.Author != "octocat" || command.CommentID != 70 || command.Name != CommandRecheck {
+		t.Errorf("unexpected command %+v", command)
+	}
+	if command := NewCommand(event("created", true, "Looks good\n/approve")); command == nil || command.Name != CommandApprove || len(command.Args) != 0 {
+		t.Errorf("expected an approve command, got %+v", command)
+	}
+	if command := NewCommand(event("created", true, "/approve cancel")); command == nil || command.Name != CommandApprove || !slices.Equal(command.Args, []string{"cancel"}) {
+		t.Errorf("expected a cancelled approve command, got %+v", command)
+	}
+	if command := NewCommand(event("created", true, "LGTM")); command != nil {
+		t.Errorf("expected comments without a command to be ignored, got %+v", command)
+	}
+	if command := NewCommand(event("edited", true, "/codeowners recheck")); command != nil {
+		t.Errorf("expected edited comments to be ignored, got %+v", command)
+	}
+	if command := NewCommand(event("created", false, "/codeowners recheck")); command != nil {
+		t.Errorf("expected issue comments to be ignored, got %+v", command)
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, ".codeowners"), []byte("* @org/base\n*.go @org/go\n? docs/** @docs-reviewer\n"), 0644); err != nil {
+		t.Fatalf("failed to write .codeowners: %v", err)
+	}
+
+	tt := []struct {
+		name              string
+		body              string
+		author            string
+		adminBypass       *owners.AdminBypass
+		expectedEvaluate  bool
+		expectedReply     string
+		expectedRequested []string
+		expectedNoReply   bool
+	}{
+		{
+			name:             "recheck",
+			body:             "/codeowners recheck",
+			expectedEvaluate: true,
+			expectedNoReply:  true,
+		},
+		{
+			name:          "explain",
+			body:          "/codeowners explain /main.go",
+			expectedReply: "Codeowners approval required for `main.go`:\n- @org/go",
+		},
+		{
+			name:          "explain optional reviewers",
+			body:          "/codeowners explain docs/README.md",
+			expectedReply: "Codeowners approval required for `docs/README.md`:\n- @org/base\n\nOptional reviewers: @docs-reviewer",
+		},
+		{
+			name:          "explain without path",
+			body:          "/codeowners explain",
+			expectedReply: commandUsage,
+		},
+		{
+			name:             "bypass by repository admin",
+			body:             "/codeowners bypass hotfix",
+			author:           "repo-admin",
+			expectedEvaluate: true,
+			expectedReply:    "🔓 Codeowners reviews bypassed by @repo-admin: hotfix",
+		},
+		{
+			name:             "bypass with different case and whitespace",
+			body:             " /codeowners  Bypass hotfix",
+			author:           "repo-admin",
+			expectedEvaluate: true,
+			expectedReply:    "🔓 Codeowners reviews bypassed by @repo-admin: hotfix",
+		},
+		{
+			name:             "bypass by allowed user",
+			body:             "/codeowners bypass release freeze",
+			author:           "Release-Manager",
+			adminBypass:      &owners.AdminBypass{Enabled: true, AllowedUsers: []string{"release-manager"}},
+			expectedEvaluate: true,
+			expectedReply:    "🔓 Codeowners reviews bypassed by @Release-Manager: release freeze",
+		},
+		{
+			name:             "scoped bypass",
+			body:             "/codeowners bypass scope=docs/,@org/docs typo fix",
+			author:           "repo-admin",
+			expectedEvaluate: true,
+			expectedReply:    "🔓 Codeowners reviews bypassed by @repo-admin (scope: docs/, @org/docs): typo fix",
+		},
+		{
+			name:          "bypass denied",
+			body:          "/codeowners bypass hotfix",
+			author:        "release-manager",
+			adminBypass:   &owners.AdminBypass{Enabled: false, AllowedUsers: []string{"release-manager"}},
+			expectedReply: "@release-manager is not allowed to bypass codeowners reviews.",
+		},
+		{
+			name:          "bypass without reason",
+			body:          "/codeowners bypass",
+			author:        "repo-admin",
+			expectedReply: "A reason is required to bypass codeowners reviews: `/codeowners bypass [scope=<path or owner>,...] <reason>`",
+		},
+		{
+			name:              "request by PR author",
+			body:              "/codeowners request @a b",
+			author:            "pr-author",
+			expectedReply:     "Requested a review from @a @b.",
+			expectedRequested: []string{"@a", "@b"},
+		},
+		{
+			name:              "request by repository admin",
+			body:              "/codeowners request @a",
+			author:            "repo-admin",
+			expectedReply:     "Requested a review from @a.",
+			expectedRequested: []string{"@a"},
+		},
+		{
+			name:          "request denied",
+			body:          "/codeowners request @org/everyone",
+			author:        "developer",
+			expectedReply: "@developer is not allowed to request reviews, only the PR author and repository admins are.",
+		},
+		{
+			name:          "unknown command",
+			body:          "/codeowners help",
+			expectedReply: commandUsage,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			app, mockClient := setupAppForTest(t, false)
+			app.config.RepoDir = repoDir
+			app.Conf.AdminBypass = tc.adminBypass
+			mockClient.pr = &github.PullRequest{User: &github.User{Login: github.Ptr("PR-Author")}}
+			command := ParseCommand(tc.body)
+			command.Author = tc.author
+
+			evaluate, err := app.runCommand(command, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if evaluate != tc.expectedEvaluate {
+				t.Errorf("expected evaluate %t, got %t", tc.expectedEvaluate, evaluate)
+			}
+			if tc.expectedNoReply {
+				if mockClient.AddCommentCalled {
+					t.Errorf("expected no reply, got %q", mockClient.AddCommentInput)
+				}
+			} else {
+				expected := withCommentMarker(commentKindCommand, "> "+command.Text+"\n\n"+tc.expectedReply)
+				if mockClient.AddCommentInput != expected {
+					t.Errorf("expected reply %q, got %q", expected, mockClient.AddCommentInput)
+				}
+			}
+			if !slices.Equal(mockClient.RequestReviewersInput, tc.expectedRequested) {
+				t.Errorf("expected requested reviewers %v, got %v", tc.expectedRequested, mockClient.RequestReviewersInput)
+			}
+		})
+	}
+}
+
+func TestBypassCommands(t *testing.T) {
+	comment := func(user, body string) *github.IssueComment {
+		return &github.IssueComment{Body: github.Ptr(body), User: &github.User{Login: github.Ptr(user)}}
+	}
+
+	tt := []struct {
+		name     string
+		comments []*github.IssueComment
+		expected bool
+	}{
+		{
+			name:     "bypass by repository admin",
+			comments: []*github.IssueComment{comment("developer", "LGTM"), comment("repo-admin", "/codeowners bypass hotfix")},
+			expected: true,
+		},
+		{
+			name:     "bypass with different case and whitespace",
+			comments: []*github.IssueComment{comment("repo-admin", " /codeowners  Bypass hotfix")},
+			expected: true,
+		},
+		{
+			name:     "other command",
+			comments: []*github.IssueComment{comment("repo-admin", "/codeowners recheck hotfix")},
+		},
+		{
+			name:     "bypass by unauthorized user",
+			comments: []*github.IssueComment{comment("developer", "/codeowners bypass hotfix")},
+		},
+		{
+			name:     "bypass without reason",
+			comments: []*github.IssueComment{comment("repo-admin", "/codeowners bypass")},
+		},
+		{
+			name:     "bot reply quoting a bypass",
+			comments: []*github.IssueComment{comment("repo-admin", withCommentMarker(commentKindCommand, "> /codeowners bypass hotfix"))},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			app, mockClient := setupAppForTest(t, false)
+			mockClient.comments = tc.comments
+			bypasses, err := app.bypassCommands()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (len(bypasses) > 0) != tc.expected {
+				t.Errorf("expected bypass %t, got %v", tc.expected, bypasses)
+			}
+		})
+	}
+}
+
+func TestRecordCommentApproval(t *testing.T) {
+	tt := []struct {
+		name             string
+		body             string
+		commentApprovals bool
+		quiet            bool
+		expected         string
+	}{
+		{
+			name:             "approve",
+			body:             "/approve",
+			commentApprovals: true,
+			expected:         gh.ApprovalRecord(70, "head-sha") + "\nRecorded the approval of @owner1 on head-sha.",
+		},
+		{name: "cancel", body: "/approve cancel", commentApprovals: true},
+		{name: "comment approvals disabled", body: "/approve"},
+		{name: "quiet mode", body: "/approve", commentApprovals: true, quiet: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			app, mockClient := setupAppForTest(t, tc.quiet)
+			app.Conf.CommentApprovals = tc.commentApprovals
+			mockClient.pr = &github.PullRequest{Head: &github.PullRequestBranch{SHA: github.Ptr("head-sha")}}
+			command := NewCommand(&github.IssueCommentEvent{
+				Action:  github.Ptr("created"),
+				Issue:   &github.Issue{Number: github.Ptr(7), PullRequestLinks: &github.PullRequestLinks{}},
+				Comment: &github.IssueComment{ID: github.Ptr[int64](70), Body: github.Ptr(tc.body), User: &github.User{Login: github.Ptr("owner1")}},
+			})
+
+			evaluate, err := app.runCommand(command, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !evaluate {
+				t.Error("expected the PR to be evaluated")
+			}
+			if mockClient.AddCommentInput != tc.expected {
+				t.Errorf("expected record %q, got %q", tc.expected, mockClient.AddCommentInput)
+			}
+		})
+	}
+}
+
+func TestRequestCommandQuiet(t *testing.T) {
+	app, mockClient := setupAppForTest(t, true)
+	mockClient.pr = &github.PullRequest{User: &github.User{Login: github.Ptr("pr-author")}}
+	command := ParseCommand("/codeowners request @a")
+	command.Author = "pr-author"
+
+	if _, err := app.runCommand(command, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mockClient.RequestReviewersCalled || len(mockClient.RequestReviewersInput) > 0 {
+		t.Errorf("expected no review request in quiet mode, got %v", mockClient.RequestReviewersInput)
+	}
+}
+
+func TestReplyToCommandQuiet(t *testing.T) {
+	app, mockClient := setupAppForTest(t, true)
+	if err := app.replyToCommand(&Command{Text: "/codeowners explain a.go"}, "reply"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mockClient.AddCommentCalled {
+		t.Errorf("expected no reply in quiet mode, got %q", mockClient.AddCommentInput)
+	}
+}
@@ -0,0 +1,273 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/google/go-github/v89/github"
+	owners "github.com/multimediallc/codeowners-plus/internal/config"
+	gh "github.com/multimediallc/codeowners-plus/internal/github"
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+	f "github.com/multimediallc/codeowners-plus/pkg/functional"
+)
+
+// ReviewStatusData is the data model of the review status comment template
+type ReviewStatusData struct {
+	// Groups are the required ownership groups, unapproved groups first
+	Groups []ReviewStatusGroup
+	// HighPriority is set when the PR has one of the high_priority_labels
+	HighPriority bool
+	// MaxReviewsMet is set when max_reviews approvals were received
+	MaxReviewsMet bool
+	// MinReviewsNeeded is min_reviews when every group approved but min_reviews is not met (0 otherwise)
+	MinReviewsNeeded int
+	CurrentApprovals int
+	// MissingApprovals is MinReviewsNeeded - CurrentApprovals
+	MissingApprovals int
+	// Bypassed is set when a full admin bypass was found
+	Bypassed bool
+	// Bypasses are the admin bypasses applied to the PR, with their reasons and scopes
+	Bypasses []*gh.Bypass
+	// SkippedReviewers were not requested because of max_open_reviews_per_user
+	SkippedReviewers      []string
+	MaxOpenReviewsPerUser int
+	// Detailed is set by detailed_reviewers, with the owners of every file still requiring review in Files
+	Detailed bool
+	Files    []FileReviewers
+	// CcReviewers are the optional reviewers, listed here instead of in cc comments with comments.fold_cc
+	CcReviewers []string
+}
+
+// ReviewStatusGroup is an ownership group, satisfied by an approval from any of its owners
+type ReviewStatusGroup struct {
+	Owners   []string
+	Approved bool
+	// Files are the changed files owned by the group
+	Files []string
+}
+
+// FileReviewers are the owners of a changed file
+type FileReviewers struct {
+	File   string
+	Owners []string
+}
+
+// CcData is the data model of the optional reviewer cc comment template
+type CcData struct {
+	Reviewers []string
+}
+
+const defaultReviewStatusTemplate = `{{if .HighPriority}}❗High Prio❗
+
+{{end}}Codeowners approval required for this PR:
+{{range $i, $group := .Groups}}{{if $i}}
+{{end}}- {{if $group.Approved}}✅ {{end}}{{join $group.Owners " or "}}{{end}}
+{{- if .MaxReviewsMet}}
+
+The PR has received the max number of required reviews. No further action is required.
+{{- end}}
+{{- if gt .MinReviewsNeeded 0}}
+
+Minimum review requirement not met. Need {{.MinReviewsNeeded}} reviews, found {{.CurrentApprovals}}. Reviews have been re-requested from owning teams, but any additional {{if gt .MissingApprovals 1}}approvals{{else}}approval{{end}} can satisfy minimum.
+{{- end}}
+{{- if .SkippedReviewers}}
+
+Not requested for review (at the limit of {{.MaxOpenReviewsPerUser}} open review requests): {{join .SkippedReviewers ", "}}
+{{- end}}
+{{- range .Bypasses}}
+
+🔓 Bypassed by @{{.User}}{{if .Scope}} (scope: {{join .Scope ", "}}){{end}}{{if .Reason}}: {{.Reason}}{{end}}
+{{- end}}
+{{- if .CcReviewers}}
+
+cc {{join .CcReviewers " "}}
+{{- end}}
+{{- if .Detailed}}
+
+<details><summary>Show detailed file reviewers</summary>
+
+{{range .Files}}- {{.File}}: [{{join .Owners " "}}]
+{{end}}
+</details>
+{{- end}}`
+
+const defaultCcTemplate = `cc {{join .Reviewers " "}}`
+
+var commentTemplateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+var (
+	defaultReviewStatus = template.Must(template.New("review_status").Funcs(commentTemplateFuncs).Parse(defaultReviewStatusTemplate))
+	defaultCc           = template.Must(template.New("cc").Funcs(commentTemplateFuncs).Parse(defaultCcTemplate))
+)
+
+// commentTemplates are the templates the PR comments are rendered with
+type commentTemplates struct {
+	reviewStatus *template.Template
+	cc           *template.Template
+}
+
+// loadCommentTemplates parses the configured comment templates, falling back to the defaults
+// (with a warning) for templates which cannot be read or parsed
+func (a *App) loadCommentTemplates(comments *owners.Comments, fileReader codeowners.FileReader) {
+	a.templates = commentTemplates{reviewStatus: defaultReviewStatus, cc: defaultCc}
+	if comments == nil {
+		return
+	}
+	a.templates.reviewStatus = a.parseCommentTemplate("review_status", comments.ReviewStatusTemplate, comments.ReviewStatusTemplateFile, fileReader, defaultReviewStatus)
+	a.templates.cc = a.parseCommentTemplate("cc", comments.CcTemplate, comments.CcTemplateFile, fileReader, defaultCc)
+}
+
+func (a *App) parseCommentTemplate(name, inline, path string, fileReader codeowners.FileReader, fallback *template.Template) *template.Template {
+	text, err := owners.ReadTemplate(inline, path, a.config.RepoDir, fileReader)
+	if err != nil {
+		a.printWarn("WARNING: Error reading %s template - using the default: %v\n", name, err)
+		return fallback
+	}
+	if text == "" {
+		return fallback
+	}
+	tmpl, err := template.New(name).Funcs(commentTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		a.printWarn("WARNING: Error parsing %s template - using the default: %v\n", name, err)
+		return fallback
+	}
+	return tmpl
+}
+
+// renderComment executes tmpl, falling back to the default template if it fails
+func (a *App) renderComment(tmpl *template.Template, fallback *template.Template, data any) (string, error) {
+	if tmpl == nil {
+		tmpl = fallback
+	}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		if tmpl == fallback {
+			return "", err
+		}
+		a.printWarn("WARNING: Error rendering %s template - using the default: %v\n", tmpl.Name(), err)
+		buffer.Reset()
+		if err := fallback.Execute(&buffer, data); err != nil {
+			return "", err
+		}
+	}
+	return buffer.String(), nil
+}
+
+// reviewStatusGroups lists the groups in the order of the original comment: unapproved groups
+// first, each sorted by their owners
+func reviewStatusGroups(groups codeowners.ReviewerGroups, fileRequired map[string]codeowners.ReviewerGroups) []ReviewStatusGroup {
+	statusGroups := make([]ReviewStatusGroup, 0, len(groups))
+	for _, group := range groups {
+		files := make([]string, 0)
+		for file, fileGroups := range fileRequired {
+			if slices.Contains(fileGroups, group) {
+				files = append(files, file)
+			}
+		}
+		slices.Sort(files)
+		statusGroups = append(statusGroups, ReviewStatusGroup{
+			Owners:   codeowners.OriginalStrings(group.Names),
+			Approved: group.Approved,
+			Files:    files,
+		})
+	}
+	slices.SortFunc(statusGroups, func(a, b ReviewStatusGroup) int {
+		if a.Approved != b.Approved {
+			if a.Approved {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(strings.Join(a.Owners, " or "), strings.Join(b.Owners, " or "))
+	})
+	return statusGroups
+}
+
+// fileReviewers lists the owners of every file, sorted by file name
+func fileReviewers(fileRequired map[string]codeowners.ReviewerGroups) []FileReviewers {
+	files := make([]FileReviewers, 0, len(fileRequired))
+	for file, groups := range fileRequired {
+		files = append(files, FileReviewers{File: file, Owners: codeowners.OriginalStrings(groups.Flatten())})
+	}
+	slices.SortFunc(files, func(a, b FileReviewers) int { return strings.Compare(a.File, b.File) })
+	return files
+}
+
+// commentMarkerSchemaVersion is bumped when the layout of the bot's comments changes incompatibly
+const commentMarkerSchemaVersion = 1
+
+// Kinds of comments posted by the bot, identified by their hidden marker
+const (
+	commentKindReviewStatus = "status"
+	commentKindCc           = "cc"
+	commentKindInline       = "inline"
+	commentKindCommand      = "command"
+)
+
+// legacyReviewStatusPrefixes are the first lines of review status comments posted before comments
+// were marked, which are adopted (and marked) on the next update
+var legacyReviewStatusPrefixes = []string{
+	"Codeowners approval required for this PR:\n",
+	"❗High Prio❗\n",
+}
+
+// commentMarker is the hidden HTML marker identifying a comment of the given kind
+func commentMarker(kind string) string {
+	return fmt.Sprintf("<!-- codeowners-plus:%s v=%d -->", kind, commentMarkerSchemaVersion)
+}
+
+// hasCommentMarker reports whether a comment starts with a marker of any kind
+func hasCommentMarker(comment string) bool {
+	return strings.HasPrefix(comment, "<!-- codeowners-plus:")
+}
+
+// commentMarkerPrefix matches the marker of the given kind for any schema version
+func commentMarkerPrefix(kind string) string {
+	return fmt.Sprintf("<!-- codeowners-plus:%s ", kind)
+}
+
+// withCommentMarker prepends the hidden marker of the given kind to a rendered comment
+func withCommentMarker(kind, comment string) string {
+	return commentMarker(kind) + "\n" + comment
+}
+
+// foldCc reports whether the optional reviewers are listed in the review status comment instead of
+// cc comments, which requires review status comments
+func (a *App) foldCc() bool {
+	return a.Conf.Comments != nil && a.Conf.Comments.FoldCc && !a.Conf.DisableReviewStatusComments
+}
+
+// removesOutdatedComments reports whether superseded bot comments are minimized or deleted
+func (a *App) removesOutdatedComments() bool {
+	return a.Conf.Comments != nil && a.Conf.Comments.Outdated != "" && a.Conf.Comments.Outdated != owners.OutdatedKeep
+}
+
+// removeOutdatedComments minimizes or deletes superseded bot comments, as set by comments.outdated
+func (a *App) removeOutdatedComments(comments []*github.IssueComment) {
+	// Only marked comments are removed, comments are otherwise kept rather than risking someone else's
+	comments = f.Filtered(comments, func(c *github.IssueComment) bool { return hasCommentMarker(c.GetBody()) })
+	if len(comments) == 0 || !a.removesOutdatedComments() {
+		return
+	}
+	switch a.Conf.Comments.Outdated {
+	case owners.OutdatedMinimize:
+		a.printDebug("Minimizing %d outdated comment(s)\n", len(comments))
+		if err := a.client.MinimizeComments(comments); err != nil {
+			a.printWarn("WARNING: Error minimizing outdated comments: %v\n", err)
+		}
+	case owners.OutdatedDelete:
+		a.printDebug("Deleting %d outdated comment(s)\n", len(comments))
+		for _, comment := range comments {
+			if err := a.client.DeleteComment(comment.GetID()); err != nil {
+				a.printWarn("WARNING: Error deleting outdated comment %d: %v\n", comment.GetID(), err)
+			}
+		}
+	default:
+		a.printWarn("WARNING: Unknown comments.outdated value %q, keeping outdated comments\n", a.Conf.Comments.Outdated)
+	}
+}
@@ -0,0 +1,212 @@
+package app
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	owners "github.com/multimediallc/codeowners-plus/internal/config"
+	gh "github.com/multimediallc/codeowners-plus/internal/github"
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+)
+
+func TestDefaultReviewStatusTemplate(t *testing.T) {
+	tt := []struct {
+		name     string
+		data     ReviewStatusData
+		expected string
+	}{
+		{
+			name: "groups",
+			data: ReviewStatusData{Groups: []ReviewStatusGroup{
+				{Owners: []string{"@b"}},
+				{Owners: []string{"@a", "@org/team"}},
+			}},
+			expected: "Codeowners approval required for this PR:\n- @b\n- @a or @org/team",
+		},
+		{
+			name: "high priority with an approved group",
+			data: ReviewStatusData{
+				HighPriority: true,
+				Groups:       []ReviewStatusGroup{{Owners: []string{"@a"}}, {Owners: []string{"@b"}, Approved: true}},
+			},
+			expected: "❗High Prio❗\n\nCodeowners approval required for this PR:\n- @a\n- ✅ @b",
+		},
+		{
+			name: "max reviews met",
+			data: ReviewStatusData{Groups: []ReviewStatusGroup{{Owners: []string{"@a"}}}, MaxReviewsMet: true},
+			expected: "Codeowners approval required for this PR:\n- @a\n\n" +
+				"The PR has received the max number of required reviews. No further action is required.",
+		},
+		{
+			name: "min reviews needed",
+			data: ReviewStatusData{MinReviewsNeeded: 3, CurrentApprovals: 1, MissingApprovals: 2},
+			expected: "Codeowners approval required for this PR:\n\n\n" +
+				"Minimum review requirement not met. Need 3 reviews, found 1. Reviews have been re-requested from owning teams, but any additional approvals can satisfy minimum.",
+		},
+		{
+			name: "skipped reviewers",
+			data: ReviewStatusData{
+				Groups:                []ReviewStatusGroup{{Owners: []string{"@a", "@b"}}},
+				SkippedReviewers:      []string{"@a"},
+				MaxOpenReviewsPerUser: 4,
+			},
+			expected: "Codeowners approval required for this PR:\n- @a or @b\n\n" +
+				"Not requested for review (at the limit of 4 open review requests): @a",
+		},
+		{
+			name: "bypasses",
+			data: ReviewStatusData{
+				Groups:   []ReviewStatusGroup{{Owners: []string{"@a"}}},
+				Bypasses: []*gh.Bypass{{User: "admin", Reason: "typo fix", Scope: []string{"docs/", "@b"}}, {User: "other-admin"}},
+			},
+			expected: "Codeowners approval required for this PR:\n- @a\n\n" +
+				"🔓 Bypassed by @admin (scope: docs/, @b): typo fix\n\n🔓 Bypassed by @other-admin",
+		},
+		{
+			name: "detailed reviewers",
+			data: ReviewStatusData{
+				Groups:   []ReviewStatusGroup{{Owners: []string{"@a"}}},
+				Detailed: true,
+				Files:    []FileReviewers{{File: "a.go", Owners: []string{"@a", "@b"}}, {File: "b.go", Owners: []string{"@a"}}},
+			},
+			expected: "Codeowners approval required for this PR:\n- @a\n\n" +
+				"<details><summary>Show detailed file reviewers</summary>\n\n- a.go: [@a @b]\n- b.go: [@a]\n\n</details>",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			if err := defaultReviewStatus.Execute(&buffer, tc.data); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buffer.String() != tc.expected {
+				t.Errorf("expected comment %q, got %q", tc.expected, buffer.String())
+			}
+		})
+	}
+}
+
+func TestReviewStatusGroups(t *testing.T) {
+	groupA := &codeowners.ReviewerGroup{Names: codeowners.NewSlugs([]string{"@a"}), Approved: true}
+	groupB := &codeowners.ReviewerGroup{Names: codeowners.NewSlugs([]string{"@b", "@c"})}
+	fileRequired := map[string]codeowners.ReviewerGroups{
+		"z.go": {groupA, groupB},
+		"y.go": {groupB},
+	}
+
+	groups := reviewStatusGroups(codeowners.ReviewerGroups{groupA, groupB}, fileRequired)
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	if strings.Join(groups[0].Owners, " ") != "@b @c" || strings.Join(groups[0].Files, " ") != "y.go z.go" || groups[0].Approved {
+		t.Errorf("expected the unapproved group first with its files, got %+v", groups[0])
+	}
+	if strings.Join(groups[1].Owners, " ") != "@a" || strings.Join(groups[1].Files, " ") != "z.go" || !groups[1].Approved {
+		t.Errorf("expected the approved group last with its files, got %+v", groups[1])
+	}
+}
+
+func TestLoadCommentTemplates(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repoDir, "status.tmpl"), []byte("Owners: {{range .Groups}}{{join .Owners \"|\"}}{{end}}"), 0644); err != nil {
+		t.Fatalf("failed to write template: %v", err)
+	}
+
+	tt := []struct {
+		name            string
+		comments        *owners.Comments
+		expectedStatus  string
+		expectedCc      string
+		expectedWarning bool
+	}{
+		{
+			name:           "defaults",
+			comments:       &owners.Comments{},
+			expectedStatus: "Codeowners approval required for this PR:\n- @a or @b",
+			expectedCc:     "cc @c @d",
+		},
+		{
+			name: "inline templates",
+			comments: &owners.Comments{
+				ReviewStatusTemplate: "{{len .Groups}} group(s) need review",
+				CcTemplate:           "FYI {{join .Reviewers \", \"}}",
+			},
+			expectedStatus: "1 group(s) need review",
+			expectedCc:     "FYI @c, @d",
+		},
+		{
+			name:           "template file",
+			comments:       &owners.Comments{ReviewStatusTemplateFile: "status.tmpl"},
+			expectedStatus: "Owners: @a|@b",
+			expectedCc:     "cc @c @d",
+		},
+		{
+			name:            "invalid template falls back to the default",
+			comments:        &owners.Comments{CcTemplate: "{{.Reviewers"},
+			expectedStatus:  "Codeowners approval required for this PR:\n- @a or @b",
+			expectedCc:      "cc @c @d",
+			expectedWarning: true,
+		},
+		{
+			name:            "template failing to render falls back to the default",
+			comments:        &owners.Comments{ReviewStatusTemplate: "{{.Unknown}}"},
+			expectedStatus:  "Codeowners approval required for this PR:\n- @a or @b",
+			expectedCc:      "cc @c @d",
+			expectedWarning: true,
+		},
+		{
+			name:            "missing template file falls back to the default",
+			comments:        &owners.Comments{CcTemplateFile: "missing.tmpl"},
+			expectedStatus:  "Codeowners approval required for this PR:\n- @a or @b",
+			expectedCc:      "cc @c @d",
+			expectedWarning: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings := &bytes.Buffer{}
+			app := &App{config: &Config{RepoDir: repoDir, InfoBuffer: io.Discard, WarningBuffer: warnings}}
+			app.loadCommentTemplates(tc.comments, nil)
+
+			status, err := app.renderComment(app.templates.reviewStatus, defaultReviewStatus, ReviewStatusData{
+				Groups: []ReviewStatusGroup{{Owners: []string{"@a", "@b"}}},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status != tc.expectedStatus {
+				t.Errorf("expected status comment %q, got %q", tc.expectedStatus, status)
+			}
+			cc, err := app.renderComment(app.templates.cc, defaultCc, CcData{Reviewers: []string{"@c", "@d"}})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cc != tc.expectedCc {
+				t.Errorf("expected cc comment %q, got %q", tc.expectedCc, cc)
+			}
+			if (warnings.Len() > 0) != tc.expectedWarning {
+				t.Errorf("expected warning %t, got %q", tc.expectedWarning, warnings.String())
+			}
+		})
+	}
+}
+
+func TestWithCommentMarker(t *testing.T) {
+	comment := withCommentMarker(commentKindReviewStatus, "Codeowners approval required for this PR:\n- @a")
+	expected := "<!-- codeowners-plus:status v=1 -->\nCodeowners approval required for this PR:\n- @a"
+	if comment != expected {
+		t.Errorf("expected comment %q, got %q", expected, comment)
+	}
+	if !strings.HasPrefix(comment, commentMarkerPrefix(commentKindReviewStatus)) {
+		t.Errorf("expected comment to match the status marker prefix, got %q", comment)
+	}
+	if strings.HasPrefix(withCommentMarker(commentKindCc, "cc @a"), commentMarkerPrefix(commentKindReviewStatus)) {
+		t.Error("expected cc comment not to match the status marker prefix")
+	}
+}
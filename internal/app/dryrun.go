@@ -0,0 +1,145 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v89/github"
+	gh "github.com/multimediallc/codeowners-plus/internal/github"
+	f "github.com/multimediallc/codeowners-plus/pkg/functional"
+)
+
+// PlanFormatText and PlanFormatJSON are the formats a dry-run plan is printed in
+const (
+	PlanFormatText = "text"
+	PlanFormatJSON = "json"
+)
+
+// PlannedAction is a GitHub change recorded instead of made in dry-run mode
+type PlannedAction struct {
+	Action string `json:"action"`
+	// Target is what the change applies to, e.g. the reviewers, labels or comment ID
+	Target string `json:"target,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// dryRunClient passes reads through to the wrapped client and records every change as a plan
+type dryRunClient struct {
+	gh.Client
+	plan []*PlannedAction
+}
+
+func newDryRunClient(client gh.Client) *dryRunClient {
+	return &dryRunClient{Client: client, plan: make([]*PlannedAction, 0)}
+}
+
+func (c *dryRunClient) record(action, target, body string) {
+	c.plan = append(c.plan, &PlannedAction{Action: action, Target: target, Body: body})
+}
+
+func (c *dryRunClient) DismissStaleReviews(staleApprovals []*gh.CurrentApproval) error {
+	for _, approval := range staleApprovals {
+		c.record("dismiss_review", fmt.Sprintf("review %d by @%s", approval.ReviewID, approval.GHLogin.Original()), "")
+	}
+	return nil
+}
+
+func (c *dryRunClient) RequestReviewers(reviewers []string) error {
+	c.record("request_reviewers", strings.Join(reviewers, ", "), "")
+	return nil
+}
+
+func (c *dryRunClient) ApprovePR() error {
+	c.record("approve_pr", "", "")
+	return nil
+}
+
+func (c *dryRunClient) AddComment(comment string) error {
+	c.record("add_comment", "", comment)
+	return nil
+}
+
+func (c *dryRunClient) UpdateComment(commentID int64, body string) error {
+	c.record("update_comment", fmt.Sprintf("comment %d", commentID), body)
+	return nil
+}
+
+func (c *dryRunClient) MinimizeComments(comments []*github.IssueComment) error {
+	ids := f.Map(comments, func(comment *github.IssueComment) string { return fmt.Sprintf("%d", comment.GetID()) })
+	c.record("minimize_comments", "comments "+strings.Join(ids, ", "), "")
+	return nil
+}
+
+func (c *dryRunClient) DeleteComment(commentID int64) error {
+	c.record("delete_comment", fmt.Sprintf("comment %d", commentID), "")
+	return nil
+}
+
+func (c *dryRunClient) CreateInlineReview(comments []*gh.InlineComment) error {
+	for _, comment := range comments {
+		c.record("add_inline_comment", fmt.Sprintf("%s:%d", comment.Path, comment.Line), comment.Body)
+	}
+	return nil
+}
+
+func (c *dryRunClient) UpdateInlineComment(commentID int64, body string) error {
+	c.record("update_inline_comment", fmt.Sprintf("comment %d", commentID), body)
+	return nil
+}
+
+func (c *dryRunClient) EnsureLabel(name, color, description string) error {
+	c.record("ensure_label", name, description)
+	return nil
+}
+
+func (c *dryRunClient) AddLabels(labels []string) error {
+	c.record("add_labels", strings.Join(labels, ", "), "")
+	return nil
+}
+
+func (c *dryRunClient) RemoveLabel(label string) error {
+	c.record("remove_label", label, "")
+	return nil
+}
+
+func (c *dryRunClient) CreateCheckRun(name string, result gh.CheckRunResult) error {
+	conclusion := "failure"
+	if result.Success {
+		conclusion = "success"
+	}
+	c.record("create_check_run", name, fmt.Sprintf("%s: %s", conclusion, result.Title))
+	return nil
+}
+
+func (c *dryRunClient) SetCommitStatus(statusContext, state, description string) error {
+	c.record("set_commit_status", statusContext, fmt.Sprintf("%s: %s", state, description))
+	return nil
+}
+
+// Plan returns the GitHub changes recorded in dry-run mode, or nil when changes are made
+func (a *App) Plan() []*PlannedAction {
+	if client, ok := a.client.(*dryRunClient); ok {
+		return client.plan
+	}
+	return nil
+}
+
+// FormatPlan renders a dry-run plan as text, one action per line followed by its indented body
+func FormatPlan(plan []*PlannedAction) string {
+	if len(plan) == 0 {
+		return "Dry run: no GitHub changes planned\n"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Dry run: %d planned GitHub change(s)\n", len(plan))
+	for _, action := range plan {
+		fmt.Fprintf(&b, "- %s", action.Action)
+		if action.Target != "" {
+			fmt.Fprintf(&b, " %s", action.Target)
+		}
+		b.WriteString("\n")
+		for line := range strings.Lines(action.Body) {
+			fmt.Fprintf(&b, "    %s\n", strings.TrimSuffix(line, "\n"))
+		}
+	}
+	return b.String()
+}
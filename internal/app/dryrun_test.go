@@ -0,0 +1,92 @@
+package app
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v89/github"
+	gh "github.com/multimediallc/codeowners-plus/internal/github"
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+)
+
+func TestDryRunClient(t *testing.T) {
+	app, mockClient := setupAppForTest(t, false)
+	mockClient.pr = &github.PullRequest{Number: github.Ptr(7)}
+	app.client = newDryRunClient(mockClient)
+
+	if app.client.PR().GetNumber() != 7 {
+		t.Errorf("expected reads to pass through, got PR %d", app.client.PR().GetNumber())
+	}
+	_ = app.client.RequestReviewers([]string{"@org/team", "@user1"})
+	_ = app.client.DismissStaleReviews([]*gh.CurrentApproval{{GHLogin: codeowners.NewSlug("user2"), ReviewID: 12}})
+	_ = app.client.AddComment("Codeowners approval required\n- @org/team")
+	_ = app.client.UpdateComment(34, "Codeowners reviews satisfied")
+	_ = app.client.ApprovePR()
+	_ = app.client.SetCommitStatus("codeowners-plus", gh.CommitStateSuccess, "Codeowners reviews satisfied")
+
+	expected := []*PlannedAction{
+		{Action: "request_reviewers", Target: "@org/team, @user1"},
+		{Action: "dismiss_review", Target: "review 12 by @user2"},
+		{Action: "add_comment", Body: "Codeowners approval required\n- @org/team"},
+		{Action: "update_comment", Target: "comment 34", Body: "Codeowners reviews satisfied"},
+		{Action: "approve_pr"},
+		{Action: "set_commit_status", Target: "codeowners-plus", Body: "success: Codeowners reviews satisfied"},
+	}
+	if !reflect.DeepEqual(app.Plan(), expected) {
+		t.Errorf("expected plan %v, got %v", expected, app.Plan())
+	}
+	if mockClient.RequestReviewersCalled || mockClient.AddCommentCalled || mockClient.UpdateCommentCalled || len(mockClient.SetCommitStatusCalls) > 0 {
+		t.Error("expected no changes to be made in dry-run mode")
+	}
+}
+
+func TestPlanWithoutDryRun(t *testing.T) {
+	app, _ := setupAppForTest(t, false)
+	if plan := app.Plan(); plan != nil {
+		t.Errorf("expected no plan, got %v", plan)
+	}
+
+	app, err := New(Config{Token: "test-token", Repo: "owner/repo", DryRun: true, InfoBuffer: io.Discard, WarningBuffer: io.Discard})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if plan := app.Plan(); plan == nil {
+		t.Error("expected an empty plan in dry-run mode, got nil")
+	}
+}
+
+func TestFormatPlan(t *testing.T) {
+	tt := []struct {
+		name     string
+		plan     []*PlannedAction
+		expected string
+	}{
+		{
+			name:     "empty plan",
+			expected: "Dry run: no GitHub changes planned\n",
+		},
+		{
+			name: "actions with targets and bodies",
+			plan: []*PlannedAction{
+				{Action: "request_reviewers", Target: "@org/team"},
+				{Action: "add_comment", Body: "Codeowners approval required\n- @org/team\n"},
+				{Action: "approve_pr"},
+			},
+			expected: "Dry run: 3 planned GitHub change(s)\n" +
+				"- request_reviewers @org/team\n" +
+				"- add_comment\n" +
+				"    Codeowners approval required\n" +
+				"    - @org/team\n" +
+				"- approve_pr\n",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := FormatPlan(tc.plan); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	owners "github.com/multimediallc/codeowners-plus/internal/config"
+	gh "github.com/multimediallc/codeowners-plus/internal/github"
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+	f "github.com/multimediallc/codeowners-plus/pkg/functional"
+)
+
+// addInlineOwnerComments posts an inline review comment naming the owners of every changed file, or
+// of every directory with changed files, as set by comments.inline_owners.  Comments from earlier
+// runs are found by their hidden marker and updated, so only new files get new comments.
+func (a *App) addInlineOwnerComments() error {
+	if a.config.Quiet || a.Conf.Comments == nil {
+		return nil
+	}
+	mode := a.Conf.Comments.InlineOwners
+	switch mode {
+	case owners.InlineOwnersOff, "":
+		return nil
+	case owners.InlineOwnersFile, owners.InlineOwnersDirectory:
+	default:
+		a.printWarn("WARNING: Unknown comments.inline_owners value %q, skipping inline owner comments\n", mode)
+		return nil
+	}
+
+	ownedFiles := a.ownedFiles
+	if ownedFiles == nil {
+		ownedFiles = a.codeowners.FileRequired()
+	}
+	comments := inlineOwnerComments(mode, ownedFiles, a.gitDiff.AllChanges())
+
+	existingComments, err := a.client.InlineComments(commentMarkerPrefix(commentKindInline))
+	if err != nil {
+		return fmt.Errorf("InlineComments Error: %v", err)
+	}
+	existingByKey := make(map[string]*gh.InlineComment, len(existingComments))
+	for _, existing := range existingComments {
+		key := inlineOwnersKey(mode, existing.Path)
+		if _, found := existingByKey[key]; !found {
+			existingByKey[key] = existing
+		}
+	}
+
+	newComments := make([]*gh.InlineComment, 0, len(comments))
+	for _, comment := range comments {
+		existing, found := existingByKey[inlineOwnersKey(mode, comment.Path)]
+		if !found {
+			newComments = append(newComments, comment)
+			continue
+		}
+		if existing.Body != comment.Body {
+			a.printDebug("Updating inline owner comment on %s\n", existing.Path)
+			if err := a.client.UpdateInlineComment(existing.ID, comment.Body); err != nil {
+				return fmt.Errorf("UpdateInlineComment Error: %v", err)
+			}
+		}
+	}
+	if len(newComments) == 0 {
+		return nil
+	}
+	a.printDebug("Adding %d inline owner comment(s)\n", len(newComments))
+	if err := a.client.CreateInlineReview(newComments); err != nil {
+		return fmt.Errorf("CreateInlineReview Error: %v", err)
+	}
+	return nil
+}
+
+// inlineOwnerComments builds the inline comments naming the required owners of every changed file
+// (or directory), on the first added line of the (first) file.  Files without added lines, such as
+// deleted files, have no line to comment on and are left out.
+func inlineOwnerComments(mode string, ownedFiles map[string]codeowners.ReviewerGroups, diffFiles []codeowners.DiffFile) []*gh.InlineComment {
+	diffFiles = slices.Clone(diffFiles)
+	slices.SortFunc(diffFiles, func(a, b codeowners.DiffFile) int { return strings.Compare(a.FileName, b.FileName) })
+
+	keys := make([]string, 0)
+	comments := make(map[string]*gh.InlineComment)
+	groups := make(map[string]codeowners.ReviewerGroups)
+	for _, file := range diffFiles {
+		fileGroups := ownedFiles[file.FileName]
+		line := firstAddedLine(file.Hunks)
+		if len(fileGroups) == 0 || line == 0 {
+			continue
+		}
+		key := inlineOwnersKey(mode, file.FileName)
+		if _, found := comments[key]; !found {
+			keys = append(keys, key)
+			comments[key] = &gh.InlineComment{Path: file.FileName, Line: line}
+		}
+		for _, group := range fileGroups {
+			if !slices.Contains(groups[key], group) {
+				groups[key] = append(groups[key], group)
+			}
+		}
+	}
+
+	inlineComments := make([]*gh.InlineComment, 0, len(keys))
+	for _, key := range keys {
+		header := "Codeowners approval required for this file:"
+		if mode == owners.InlineOwnersDirectory {
+			header = fmt.Sprintf("Codeowners approval required for changed files in `%s/`:", key)
+			if key == "." {
+				header = "Codeowners approval required for changed files in the repository root:"
+			}
+		}
+		lines := f.Map(reviewStatusGroups(groups[key], nil), func(group ReviewStatusGroup) string {
+			if group.Approved {
+				return "- ✅ " + strings.Join(group.Owners, " or ")
+			}
+			return "- " + strings.Join(group.Owners, " or ")
+		})
+		comment := comments[key]
+		comment.Body = withCommentMarker(commentKindInline, header+"\n"+strings.Join(f.RemoveDuplicates(lines), "\n"))
+		inlineComments = append(inlineComments, comment)
+	}
+	return inlineComments
+}
+
+// inlineOwnersKey identifies the inline comment covering a file: the file itself, or its directory
+func inlineOwnersKey(mode string, file string) string {
+	if mode == owners.InlineOwnersDirectory {
+		return path.Dir(file)
+	}
+	return file
+}
+
+// firstAddedLine is the first line added or changed by the hunks (0 when there is none)
+func firstAddedLine(hunks []codeowners.HunkRange) int {
+	for _, hunk := range hunks {
+		if hunk.Start > 0 && hunk.End >= hunk.Start {
+			return hunk.Start
+		}
+	}
+	return 0
+}
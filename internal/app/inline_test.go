@@ -0,0 +1,108 @@
+package app
+
+import (
+	"io"
+	"testing"
+
+	owners "github.com/multimediallc/codeowners-plus/internal/config"
+	gh "github.com/multimediallc/codeowners-plus/internal/github"
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+)
+
+func TestInlineOwnerComments(t *testing.T) {
+	groupA := &codeowners.ReviewerGroup{Names: codeowners.NewSlugs([]string{"@a"}), Approved: true}
+	groupB := &codeowners.ReviewerGroup{Names: codeowners.NewSlugs([]string{"@b", "@org/c"})}
+	ownedFiles := map[string]codeowners.ReviewerGroups{
+		"src/z.go":   {groupB},
+		"src/y.go":   {groupA, groupB},
+		"README.md":  {groupA},
+		"deleted.go": {groupB},
+	}
+	diffFiles := []codeowners.DiffFile{
+		{FileName: "src/z.go", Hunks: []codeowners.HunkRange{{Start: 4, End: 3}, {Start: 10, End: 12}}},
+		{FileName: "src/y.go", Hunks: []codeowners.HunkRange{{Start: 7, End: 7}}},
+		{FileName: "README.md", Hunks: []codeowners.HunkRange{{Start: 1, End: 2}}},
+		{FileName: "deleted.go", Hunks: []codeowners.HunkRange{{Start: 0, End: -1}}},
+		{FileName: "unowned.go", Hunks: []codeowners.HunkRange{{Start: 1, End: 1}}},
+	}
+
+	tt := []struct {
+		name     string
+		mode     string
+		expected []gh.InlineComment
+	}{
+		{
+			name: "per file",
+			mode: owners.InlineOwnersFile,
+			expected: []gh.InlineComment{
+				{Path: "README.md", Line: 1, Body: "<!-- codeowners-plus:inline v=1 -->\nCodeowners approval required for this file:\n- ✅ @a"},
+				{Path: "src/y.go", Line: 7, Body: "<!-- codeowners-plus:inline v=1 -->\nCodeowners approval required for this file:\n- @b or @org/c\n- ✅ @a"},
+				{Path: "src/z.go", Line: 10, Body: "<!-- codeowners-plus:inline v=1 -->\nCodeowners approval required for this file:\n- @b or @org/c"},
+			},
+		},
+		{
+			name: "per directory",
+			mode: owners.InlineOwnersDirectory,
+			expected: []gh.InlineComment{
+				{Path: "README.md", Line: 1, Body: "<!-- codeowners-plus:inline v=1 -->\nCodeowners approval required for changed files in the repository root:\n- ✅ @a"},
+				{Path: "src/y.go", Line: 7, Body: "<!-- codeowners-plus:inline v=1 -->\nCodeowners approval required for changed files in `src/`:\n- @b or @org/c\n- ✅ @a"},
+			},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			comments := inlineOwnerComments(tc.mode, ownedFiles, diffFiles)
+			if len(comments) != len(tc.expected) {
+				t.Fatalf("expected %d comments, got %d", len(tc.expected), len(comments))
+			}
+			for i, comment := range comments {
+				if *comment != tc.expected[i] {
+					t.Errorf("expected comment %+v, got %+v", tc.expected[i], *comment)
+				}
+			}
+		})
+	}
+}
+
+func TestAddInlineOwnerComments(t *testing.T) {
+	group := &codeowners.ReviewerGroup{Names: codeowners.NewSlugs([]string{"@a"})}
+	mockGH := &mockGitHubClient{
+		inlineComments: []*gh.InlineComment{
+			{ID: 1, Path: "file1.go", Line: 1, Body: "<!-- codeowners-plus:inline v=1 -->\nCodeowners approval required for this file:\n- @b"},
+			{ID: 2, Path: "file2.go", Line: 1, Body: "<!-- codeowners-plus:inline v=1 -->\nCodeowners approval required for this file:\n- @a"},
+		},
+	}
+	app := &App{
+		config:  &Config{InfoBuffer: io.Discard, WarningBuffer: io.Discard},
+		client:  mockGH,
+		gitDiff: mockGitDiff{changes: []string{"file1.go", "file2.go", "file3.go"}},
+		codeowners: &mockCodeOwners{fileRequiredMap: map[string]codeowners.ReviewerGroups{
+			"file1.go": {group},
+			"file2.go": {group},
+			"file3.go": {group},
+		}},
+		Conf: &owners.Config{Comments: &owners.Comments{InlineOwners: owners.InlineOwnersFile}},
+	}
+
+	if err := app.addInlineOwnerComments(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedBody := "<!-- codeowners-plus:inline v=1 -->\nCodeowners approval required for this file:\n- @a"
+	if len(mockGH.UpdatedInlineComments) != 1 || mockGH.UpdatedInlineComments[1] != expectedBody {
+		t.Errorf("expected only comment 1 to be updated, got %v", mockGH.UpdatedInlineComments)
+	}
+	if len(mockGH.CreateInlineReviewInput) != 1 || mockGH.CreateInlineReviewInput[0].Path != "file3.go" {
+		t.Errorf("expected a review with a comment on file3.go, got %v", mockGH.CreateInlineReviewInput)
+	}
+
+	// Nothing is posted in quiet mode
+	mockGH.CreateInlineReviewInput = nil
+	app.config.Quiet = true
+	if err := app.addInlineOwnerComments(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mockGH.CreateInlineReviewInput != nil {
+		t.Errorf("expected no review in quiet mode, got %v", mockGH.CreateInlineReviewInput)
+	}
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+	f "github.com/multimediallc/codeowners-plus/pkg/functional"
+)
+
+// OwnerLabelData is the data model of the owner_labels.template
+type OwnerLabelData struct {
+	// Team is the team slug, e.g. "backend" for "@org/backend"
+	Team  string
+	Org   string
+	Owner string
+}
+
+// applyOwnerLabels labels the PR with the owning teams of the changes, as named by the
+// owner_labels.template, and removes owner labels of teams which no longer own any change.
+// Missing labels are created in the repository.
+func (a *App) applyOwnerLabels(owners []codeowners.Slug) error {
+	ownerLabels := a.Conf.OwnerLabels
+	if a.config.Quiet || ownerLabels == nil || !ownerLabels.Enabled {
+		return nil
+	}
+	tmpl, err := template.New("owner_label").Funcs(commentTemplateFuncs).Option("missingkey=error").Parse(ownerLabels.Template)
+	if err != nil {
+		return fmt.Errorf("failed to parse owner_labels.template: %w", err)
+	}
+	pattern, err := ownerLabelPattern(tmpl)
+	if err != nil {
+		return fmt.Errorf("failed to parse owner_labels.template: %w", err)
+	}
+
+	labelOwners := make(map[string]string)
+	desired := make([]string, 0)
+	for _, owner := range f.Filtered(owners, codeowners.Slug.IsTeam) {
+		label, err := renderOwnerLabel(tmpl, owner.Original())
+		if err != nil {
+			return fmt.Errorf("failed to render owner_labels.template: %w", err)
+		}
+		if !slices.ContainsFunc(desired, func(d string) bool { return strings.EqualFold(d, label) }) {
+			desired = append(desired, label)
+			labelOwners[label] = owner.Original()
+		}
+	}
+
+	current, err := a.client.Labels()
+	if err != nil {
+		return fmt.Errorf("Labels Error: %v", err)
+	}
+	hasLabel := func(labels []string) func(string) bool {
+		return func(label string) bool {
+			return slices.ContainsFunc(labels, func(l string) bool { return strings.EqualFold(l, label) })
+		}
+	}
+	toAdd := f.Filtered(desired, func(label string) bool { return !hasLabel(current)(label) })
+	toRemove := f.Filtered(current, func(label string) bool { return pattern.MatchString(label) && !hasLabel(desired)(label) })
+
+	for _, label := range toAdd {
+		if err := a.client.EnsureLabel(label, ownerLabels.Color, fmt.Sprintf("Changes owned by %s", labelOwners[label])); err != nil {
+			return fmt.Errorf("EnsureLabel Error: %v", err)
+		}
+	}
+	if len(toAdd) > 0 {
+		a.printDebug("Adding Owner Labels: %s\n", toAdd)
+		if err := a.client.AddLabels(toAdd); err != nil {
+			return fmt.Errorf("AddLabels Error: %v", err)
+		}
+	}
+	for _, label := range toRemove {
+		a.printDebug("Removing Owner Label: %s\n", label)
+		if err := a.client.RemoveLabel(label); err != nil {
+			return fmt.Errorf("RemoveLabel Error: %v", err)
+		}
+	}
+	return nil
+}
+
+func renderOwnerLabel(tmpl *template.Template, owner string) (string, error) {
+	org, team, _ := strings.Cut(strings.TrimPrefix(owner, "@"), "/")
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, OwnerLabelData{Team: team, Org: org, Owner: owner}); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buffer.String()), nil
+}
+
+// ownerLabelPattern matches the labels the template renders for any team, so owner labels of
+// teams which no longer own any change can be recognized
+func ownerLabelPattern(tmpl *template.Template) (*regexp.Regexp, error) {
+	placeholders := OwnerLabelData{Team: "\x00", Org: "\x01", Owner: "\x02"}
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, placeholders); err != nil {
+		return nil, err
+	}
+	pattern := regexp.QuoteMeta(strings.TrimSpace(buffer.String()))
+	for _, placeholder := range []string{placeholders.Team, placeholders.Org, placeholders.Owner} {
+		pattern = strings.ReplaceAll(pattern, placeholder, ".+")
+	}
+	return regexp.Compile("(?i)^" + pattern + "$")
+}
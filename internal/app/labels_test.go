@@ -0,0 +1,106 @@
+package app
+
+import (
+	"io"
+	"slices"
+	"testing"
+	"text/template"
+
+	"github.com/google/go-github/v89/github"
+	owners "github.com/multimediallc/codeowners-plus/internal/config"
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+)
+
+func TestApplyOwnerLabels(t *testing.T) {
+	tt := []struct {
+		name             string
+		template         string
+		currentLabels    []string
+		expectedEnsured  []string
+		expectedAdded    []string
+		expectedRemoved  []string
+		expectedDisabled bool
+	}{
+		{
+			name:            "adds labels for teams",
+			template:        "owner:{{.Team}}",
+			currentLabels:   []string{"bug"},
+			expectedEnsured: []string{"owner:backend", "owner:payments"},
+			expectedAdded:   []string{"owner:backend", "owner:payments"},
+		},
+		{
+			name:            "removes labels of teams which no longer own changes",
+			template:        "owner:{{.Team}}",
+			currentLabels:   []string{"Owner:Backend", "owner:frontend", "ownership"},
+			expectedEnsured: []string{"owner:payments"},
+			expectedAdded:   []string{"owner:payments"},
+			expectedRemoved: []string{"owner:frontend"},
+		},
+		{
+			name:            "template with org",
+			template:        "{{.Org}}/{{.Team}}",
+			currentLabels:   []string{"org/backend", "other/frontend", "bug"},
+			expectedEnsured: []string{"org/payments"},
+			expectedAdded:   []string{"org/payments"},
+			expectedRemoved: []string{"other/frontend"},
+		},
+		{
+			name:             "disabled",
+			template:         "owner:{{.Team}}",
+			currentLabels:    []string{"owner:frontend"},
+			expectedDisabled: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			labels := make([]*github.Label, 0, len(tc.currentLabels))
+			for _, label := range tc.currentLabels {
+				labels = append(labels, &github.Label{Name: github.Ptr(label)})
+			}
+			mockGH := &mockGitHubClient{pr: &github.PullRequest{Labels: labels}}
+			app := &App{
+				config: &Config{InfoBuffer: io.Discard, WarningBuffer: io.Discard},
+				client: mockGH,
+				Conf: &owners.Config{OwnerLabels: &owners.OwnerLabels{
+					Enabled:  !tc.expectedDisabled,
+					Template: tc.template,
+					Color:    "ededed",
+				}},
+			}
+
+			err := app.applyOwnerLabels(codeowners.NewSlugs([]string{"@org/backend", "@alice", "@org/payments", "@org/backend"}))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(mockGH.EnsuredLabels, tc.expectedEnsured) {
+				t.Errorf("expected ensured labels %v, got %v", tc.expectedEnsured, mockGH.EnsuredLabels)
+			}
+			if !slices.Equal(mockGH.AddedLabels, tc.expectedAdded) {
+				t.Errorf("expected added labels %v, got %v", tc.expectedAdded, mockGH.AddedLabels)
+			}
+			if !slices.Equal(mockGH.RemovedLabels, tc.expectedRemoved) {
+				t.Errorf("expected removed labels %v, got %v", tc.expectedRemoved, mockGH.RemovedLabels)
+			}
+		})
+	}
+}
+
+func TestOwnerLabelPattern(t *testing.T) {
+	tmpl := template.Must(template.New("owner_label").Parse("team: {{.Team}} (*)"))
+	pattern, err := ownerLabelPattern(tmpl)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for label, expected := range map[string]bool{
+		"team: backend (*)": true,
+		"TEAM: backend (*)": true,
+		"team: backend":     false,
+		"team: a-b (*)":     true,
+		"team:  (*)":        false,
+	} {
+		if pattern.MatchString(label) != expected {
+			t.Errorf("expected %q to match %t", label, expected)
+		}
+	}
+}
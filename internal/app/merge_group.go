@@ -0,0 +1,89 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"github.com/google/go-github/v89/github"
+)
+
+// MergeGroupEventName is the GitHub Actions event triggered when a PR is checked in the merge queue
+const MergeGroupEventName = "merge_group"
+
+// Merge queue branches are named gh-readonly-queue/<base branch>/pr-<number>-<base sha>
+var mergeGroupHeadRefPattern = regexp.MustCompile(`^(?:refs/heads/)?gh-readonly-queue/.+/pr-(\d+)-[0-9a-f]+$`)
+
+// The queue references the PR in the subject of merge commits ("Merge pull request #12 from ...") and
+// squash commits ("Title (#12)").  Other references, e.g. "Fixes #7", are not PRs of the group.
+var (
+	mergeCommitSubjectPattern  = regexp.MustCompile(`^Merge pull request #(\d+)\b`)
+	squashCommitSubjectPattern = regexp.MustCompile(`\(#(\d+)\)$`)
+)
+
+// MergeGroup is the temporary merge commit a PR is checked on in the merge queue
+type MergeGroup struct {
+	PR      int
+	HeadRef string
+	HeadSHA string
+	// BaseSHA is the parent of the merge group, so BaseSHA...HeadSHA is the PR's change as it will be merged
+	BaseSHA string
+}
+
+// ReadMergeGroupEvent reads the merge_group webhook payload at eventPath (GITHUB_EVENT_PATH)
+func ReadMergeGroupEvent(eventPath string) (*MergeGroup, error) {
+	data, err := os.ReadFile(eventPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading merge_group event: %w", err)
+	}
+	var event github.MergeGroupEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("error parsing merge_group event: %w", err)
+	}
+	return NewMergeGroup(event.GetMergeGroup())
+}
+
+// NewMergeGroup resolves the PR of a merge group from its head ref.  Only the PR named by the head ref is
+// evaluated, so groups containing other PRs are rejected by CheckSinglePR.
+func NewMergeGroup(mergeGroup *github.MergeGroup) (*MergeGroup, error) {
+	headRef := mergeGroup.GetHeadRef()
+	match := mergeGroupHeadRefPattern.FindStringSubmatch(headRef)
+	if match == nil {
+		return nil, fmt.Errorf("merge group head ref %q does not reference a PR", headRef)
+	}
+	pr, err := strconv.Atoi(match[1])
+	if err != nil {
+		return nil, fmt.Errorf("merge group head ref %q does not reference a PR: %w", headRef, err)
+	}
+	return &MergeGroup{
+		PR:      pr,
+		HeadRef: headRef,
+		HeadSHA: mergeGroup.GetHeadSHA(),
+		BaseSHA: mergeGroup.GetBaseSHA(),
+	}, nil
+}
+
+// CheckSinglePR fails when the merge group contains other PRs than its own, since only the approvals of
+// one PR are evaluated.  subjects are the first-parent commits of the group, which the queue adds for each
+// PR: a merge or squash commit referencing the PR, or the PR's commits when rebasing.
+func (m *MergeGroup) CheckSinglePR(prCommits int, subjects []string) error {
+	for _, subject := range subjects {
+		match := mergeCommitSubjectPattern.FindStringSubmatch(subject)
+		if match == nil {
+			match = squashCommitSubjectPattern.FindStringSubmatch(subject)
+		}
+		if match == nil {
+			continue
+		}
+		if pr, err := strconv.Atoi(match[1]); err == nil && pr != m.PR {
+			return fmt.Errorf("merge group %s also contains PR #%d, only merge groups of a single PR are supported", m.HeadRef, pr)
+		}
+	}
+	if len(subjects) > 1 && len(subjects) != prCommits {
+		return fmt.Errorf("merge group %s has %d commits but PR #%d has %d, only merge groups of a single PR are supported",
+			m.HeadRef, len(subjects), m.PR, prCommits)
+	}
+	return nil
+}
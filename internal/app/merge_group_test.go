@@ -0,0 +1,163 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v89/github"
+)
+
+func TestNewMergeGroup(t *testing.T) {
+	tt := []struct {
+		name        string
+		headRef     string
+		expectedPR  int
+		expectError bool
+	}{
+		{
+			name:       "full ref",
+			headRef:    "refs/heads/gh-readonly-queue/main/pr-123-f0e1d2c3b4a5968778695a4b3c2d1e0f12345678",
+			expectedPR: 123,
+		},
+		{
+			name:       "short ref with nested base branch",
+			headRef:    "gh-readonly-queue/release/1.0/pr-7-f0e1d2c3b4a5968778695a4b3c2d1e0f12345678",
+			expectedPR: 7,
+		},
+		{
+			name:        "not a merge queue branch",
+			headRef:     "refs/heads/feature/pr-123-abc",
+			expectError: true,
+		},
+		{
+			name:        "missing PR number",
+			headRef:     "refs/heads/gh-readonly-queue/main/pr--abc",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			mergeGroup, err := NewMergeGroup(&github.MergeGroup{
+				HeadRef: github.Ptr(tc.headRef),
+				HeadSHA: github.Ptr("head"),
+				BaseSHA: github.Ptr("base"),
+			})
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("expected error, got merge group for PR %d", mergeGroup.PR)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if mergeGroup.PR != tc.expectedPR {
+				t.Errorf("expected PR %d, got %d", tc.expectedPR, mergeGroup.PR)
+			}
+			if mergeGroup.HeadSHA != "head" || mergeGroup.BaseSHA != "base" {
+				t.Errorf("expected head and base SHAs to be kept, got %s...%s", mergeGroup.BaseSHA, mergeGroup.HeadSHA)
+			}
+		})
+	}
+}
+
+func TestReadMergeGroupEvent(t *testing.T) {
+	eventPath := filepath.Join(t.TempDir(), "event.json")
+	payload := `{
+  "action": "checks_requested",
+  "merge_group": {
+    "head_sha": "ec26c3e57ca3a959ca5aad62de7213c562f8c821",
+    "head_ref": "refs/heads/gh-readonly-queue/main/pr-104-f95f852bd8fca8fcc58a9a2d6c842781e32a215e",
+    "base_sha": "f95f852bd8fca8fcc58a9a2d6c842781e32a215e",
+    "base_ref": "refs/heads/main"
+  }
+}`
+	if err := os.WriteFile(eventPath, []byte(payload), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mergeGroup, err := ReadMergeGroupEvent(eventPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := MergeGroup{
+		PR:      104,
+		HeadRef: "refs/heads/gh-readonly-queue/main/pr-104-f95f852bd8fca8fcc58a9a2d6c842781e32a215e",
+		HeadSHA: "ec26c3e57ca3a959ca5aad62de7213c562f8c821",
+		BaseSHA: "f95f852bd8fca8fcc58a9a2d6c842781e32a215e",
+	}
+	if *mergeGroup != expected {
+		t.Errorf("expected %+v, got %+v", expected, *mergeGroup)
+	}
+
+	if _, err := ReadMergeGroupEvent(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected error reading a missing event")
+	}
+}
+
+func TestMergeGroupCheckSinglePR(t *testing.T) {
+	mergeGroup := &MergeGroup{PR: 12, HeadRef: "refs/heads/gh-readonly-queue/main/pr-12-f95f852bd8fca8fcc58a9a2d6c842781e32a215e"}
+	tt := []struct {
+		name        string
+		prCommits   int
+		subjects    []string
+		expectError bool
+	}{
+		{
+			name:      "merge commit",
+			prCommits: 3,
+			subjects:  []string{"Merge pull request #12 from org/feature"},
+		},
+		{
+			name:      "squash commit",
+			prCommits: 3,
+			subjects:  []string{"Add feature (#12)"},
+		},
+		{
+			name:      "squash commit referencing an issue",
+			prCommits: 3,
+			subjects:  []string{"Fix #7 crash (#12)"},
+		},
+		{
+			name:      "rebased commits referencing an issue",
+			prCommits: 2,
+			subjects:  []string{"Fixes #34", "Add feature"},
+		},
+		{
+			name:      "rebased commits",
+			prCommits: 2,
+			subjects:  []string{"Add tests", "Add feature"},
+		},
+		{
+			name:        "merge commits of several PRs",
+			prCommits:   1,
+			subjects:    []string{"Merge pull request #12 from org/feature", "Merge pull request #11 from org/other"},
+			expectError: true,
+		},
+		{
+			name:        "squash commits of several PRs with as many commits as the PR",
+			prCommits:   2,
+			subjects:    []string{"Add feature (#12)", "Fix typo (#11)"},
+			expectError: true,
+		},
+		{
+			name:        "rebased commits of several PRs",
+			prCommits:   2,
+			subjects:    []string{"Add tests", "Add feature", "Fix typo"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := mergeGroup.CheckSinglePR(tc.prCommits, tc.subjects)
+			if tc.expectError && err == nil {
+				t.Error("expected error, got nil")
+			} else if !tc.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package owners
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// AvailabilityFile lists users who are unavailable for reviews, e.g. while on vacation
+type AvailabilityFile struct {
+	Unavailable []Absence
+}
+
+// Absence is a date range, inclusive on both ends, during which a user is unavailable.
+// A zero From or Until leaves that end of the range open.
+type Absence struct {
+	User  string
+	From  time.Time
+	Until time.Time
+}
+
+// rawAbsence holds an absence as decoded, since dates are strings in JSON, timestamps in YAML
+// and local dates in TOML
+type rawAbsence struct {
+	User  string `toml:"user" json:"user" yaml:"user"`
+	From  any    `toml:"from" json:"from" yaml:"from"`
+	Until any    `toml:"until" json:"until" yaml:"until"`
+}
+
+// ReadAvailabilityFile reads an availability file in YAML, JSON or TOML format, chosen by file
+// extension, with dates written as YYYY-MM-DD:
+//
+//	unavailable:
+//	  - user: alice
+//	    from: 2026-10-01
+//	    until: 2026-10-14
+//
+// Paths are resolved the same way as for ReadTeamRoster.
+func ReadAvailabilityFile(repoDir string, path string, fileReader codeowners.FileReader) (*AvailabilityFile, error) {
+	raw := struct {
+		Unavailable []rawAbsence `toml:"unavailable" json:"unavailable" yaml:"unavailable"`
+	}{}
+	if err := readDataFile(repoDir, path, fileReader, "availability file", &raw); err != nil {
+		return nil, err
+	}
+
+	availability := &AvailabilityFile{Unavailable: make([]Absence, 0, len(raw.Unavailable))}
+	for _, entry := range raw.Unavailable {
+		if entry.User == "" {
+			return nil, fmt.Errorf("error parsing availability file %s: entry without a user", path)
+		}
+		from, err := parseAbsenceDate(entry.From)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing availability file %s: %s from: %w", path, entry.User, err)
+		}
+		until, err := parseAbsenceDate(entry.Until)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing availability file %s: %s until: %w", path, entry.User, err)
+		}
+		availability.Unavailable = append(availability.Unavailable, Absence{
+			User:  strings.ToLower(strings.TrimPrefix(entry.User, "@")),
+			From:  from,
+			Until: until,
+		})
+	}
+	return availability, nil
+}
+
+func parseAbsenceDate(value any) (time.Time, error) {
+	switch date := value.(type) {
+	case nil:
+		return time.Time{}, nil
+	case string:
+		return time.Parse(time.DateOnly, date)
+	case time.Time:
+		return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), nil
+	case toml.LocalDate:
+		return time.Date(date.Year, time.Month(date.Month), date.Day, 0, 0, 0, 0, time.UTC), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid date %v", value)
+	}
+}
+
+// IsUnavailable reports whether the user is absent on the (UTC) date of now
+func (a *AvailabilityFile) IsUnavailable(user string, now time.Time) bool {
+	user = strings.ToLower(strings.TrimPrefix(user, "@"))
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, absence := range a.Unavailable {
+		if absence.User != user {
+			continue
+		}
+		if !absence.From.IsZero() && today.Before(absence.From) {
+			continue
+		}
+		if !absence.Until.IsZero() && today.After(absence.Until) {
+			continue
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package owners
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadAvailabilityFile(t *testing.T) {
+	tt := []struct {
+		name          string
+		path          string
+		content       string
+		expectedUntil string
+		expectedErr   bool
+	}{
+		{
+			name: "yaml availability",
+			path: "availability.yaml",
+			content: `
+unavailable:
+  - user: "@Alice"
+    from: 2026-10-01
+    until: 2026-10-14
+`,
+			expectedUntil: "2026-10-14",
+		},
+		{
+			name:          "json availability",
+			path:          "availability.json",
+			content:       `{"unavailable": [{"user": "alice", "from": "2026-10-01", "until": "2026-10-14"}]}`,
+			expectedUntil: "2026-10-14",
+		},
+		{
+			name: "toml availability",
+			path: "availability.toml",
+			content: `
+[[unavailable]]
+user = "alice"
+from = 2026-10-01
+until = "2026-10-14"
+`,
+			expectedUntil: "2026-10-14",
+		},
+		{
+			name: "invalid date",
+			path: "availability.yaml",
+			content: `
+unavailable:
+  - user: alice
+    from: next week
+`,
+			expectedErr: true,
+		},
+		{
+			name:        "missing user",
+			path:        "availability.json",
+			content:     `{"unavailable": [{"from": "2026-10-01"}]}`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := &mockConfigFileReader{files: map[string]string{"repo/" + tc.path: tc.content}}
+			availability, err := ReadAvailabilityFile("repo", tc.path, reader)
+			if tc.expectedErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(availability.Unavailable) != 1 {
+				t.Fatalf("expected 1 absence, got %d", len(availability.Unavailable))
+			}
+			absence := availability.Unavailable[0]
+			if absence.User != "alice" {
+				t.Errorf("expected user alice, got %s", absence.User)
+			}
+			if got := absence.From.Format(time.DateOnly); got != "2026-10-01" {
+				t.Errorf("expected from 2026-10-01, got %s", got)
+			}
+			if got := absence.Until.Format(time.DateOnly); got != tc.expectedUntil {
+				t.Errorf("expected until %s, got %s", tc.expectedUntil, got)
+			}
+		})
+	}
+}
+
+func TestAvailabilityFileIsUnavailable(t *testing.T) {
+	date := func(value string) time.Time {
+		parsed, _ := time.Parse(time.DateOnly, value)
+		return parsed
+	}
+	availability := &AvailabilityFile{Unavailable: []Absence{
+		{User: "alice", From: date("2026-10-01"), Until: date("2026-10-14")},
+		{User: "bob", From: date("2026-10-10")},
+	}}
+
+	tt := []struct {
+		name     string
+		user     string
+		now      time.Time
+		expected bool
+	}{
+		{name: "before the range", user: "alice", now: date("2026-09-30"), expected: false},
+		{name: "first day", user: "@Alice", now: date("2026-10-01"), expected: true},
+		{name: "last day", user: "alice", now: date("2026-10-14").Add(23 * time.Hour), expected: true},
+		{name: "after the range", user: "alice", now: date("2026-10-15"), expected: false},
+		{name: "open ended", user: "bob", now: date("2027-01-01"), expected: true},
+		{name: "not listed", user: "carol", now: date("2026-10-05"), expected: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := availability.IsUnavailable(tc.user, tc.now); got != tc.expected {
+				t.Errorf("expected unavailable %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
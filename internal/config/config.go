@@ -8,30 +8,138 @@ import (
 )
 
 type Config struct {
-	MaxReviews                  *int         `toml:"max_reviews"`
-	MinReviews                  *int         `toml:"min_reviews"`
-	UnskippableReviewers        []string     `toml:"unskippable_reviewers"`
-	Ignore                      []string     `toml:"ignore"`
-	Enforcement                 *Enforcement `toml:"enforcement"`
-	HighPriorityLabels          []string     `toml:"high_priority_labels"`
-	AdminBypass                 *AdminBypass `toml:"admin_bypass"`
-	DetailedReviewers           bool         `toml:"detailed_reviewers"`
-	DisableSmartDismissal       bool         `toml:"disable_smart_dismissal"`
-	RequireBothBranchReviewers  bool         `toml:"require_both_branch_reviewers"`
-	SuppressUnownedWarning      bool         `toml:"suppress_unowned_warning"`
-	AllowSelfApproval           bool         `toml:"allow_self_approval"`
-	SelfApprovalViaTeams        bool         `toml:"self_approval_via_teams"`
-	DisableReviewStatusComments bool         `toml:"disable_review_status_comments"`
+	MaxReviews                  *int            `toml:"max_reviews"`
+	MinReviews                  *int            `toml:"min_reviews"`
+	UnskippableReviewers        []string        `toml:"unskippable_reviewers"`
+	Ignore                      []string        `toml:"ignore"`
+	Enforcement                 *Enforcement    `toml:"enforcement"`
+	HighPriorityLabels          []string        `toml:"high_priority_labels"`
+	AdminBypass                 *AdminBypass    `toml:"admin_bypass"`
+	DetailedReviewers           bool            `toml:"detailed_reviewers"`
+	DisableSmartDismissal       bool            `toml:"disable_smart_dismissal"`
+	RequireBothBranchReviewers  bool            `toml:"require_both_branch_reviewers"`
+	SuppressUnownedWarning      bool            `toml:"suppress_unowned_warning"`
+	AllowSelfApproval           bool            `toml:"allow_self_approval"`
+	SelfApprovalViaTeams        bool            `toml:"self_approval_via_teams"`
+	CommentApprovals            bool            `toml:"comment_approvals"`
+	DisableReviewStatusComments bool            `toml:"disable_review_status_comments"`
+	TeamRoster                  string          `toml:"team_roster"`
+	ChildTeamDepth              int             `toml:"child_team_depth"`
+	ReviewRequests              *ReviewRequests `toml:"review_requests"`
+	Availability                *Availability   `toml:"availability"`
+	Comments                    *Comments       `toml:"comments"`
+	OwnerLabels                 *OwnerLabels    `toml:"owner_labels"`
 }
 
 type Enforcement struct {
-	Approval  bool `toml:"approval"`
-	FailCheck bool `toml:"fail_check"`
+	Approval     bool   `toml:"approval"`
+	FailCheck    bool   `toml:"fail_check"`
+	CheckRun     bool   `toml:"check_run"`
+	CheckRunName string `toml:"check_run_name"`
+	// CommitStatus posts a commit status on the PR head commit under CommitStatusContext
+	CommitStatus        bool   `toml:"commit_status"`
+	CommitStatusContext string `toml:"commit_status_context"`
+}
+
+// Preferences for picking reviewers in ReviewRequests.Prefer
+const (
+	PreferCurrentlyRequested = "currently_requested"
+	PreferIndividuals        = "individuals"
+	PreferTeams              = "teams"
+)
+
+// Strategies for picking a team member in ReviewRequests.Assignment
+const (
+	AssignmentRoundRobin  = "round_robin"
+	AssignmentLeastLoaded = "least_loaded"
+)
+
+// Handling of superseded bot comments in Comments.Outdated
+const (
+	OutdatedKeep     = "keep"
+	OutdatedMinimize = "minimize"
+	OutdatedDelete   = "delete"
+)
+
+// Granularity of the inline owner comments in Comments.InlineOwners
+const (
+	InlineOwnersOff       = "off"
+	InlineOwnersFile      = "file"
+	InlineOwnersDirectory = "directory"
+)
+
+type ReviewRequests struct {
+	// Minimal requests a small set of reviewers covering every unapproved ownership group,
+	// instead of every reviewer in every group
+	Minimal bool `toml:"minimal"`
+	// Prefer orders the reviewers picked for a minimal set, most important preference first
+	Prefer []string `toml:"prefer"`
+	// AssignIndividuals requests a single member of an owning team instead of the whole team
+	AssignIndividuals bool `toml:"assign_individuals"`
+	// Assignment is the strategy for picking the team member
+	Assignment string `toml:"assignment"`
+	// Exclude lists users which are never picked from a team
+	Exclude []string `toml:"exclude"`
+	// MaxOpenReviewsPerUser skips individuals with this many open review requests in the repository,
+	// in favor of the other members of their ownership groups (0 for no limit)
+	MaxOpenReviewsPerUser int `toml:"max_open_reviews_per_user"`
+}
+
+type Availability struct {
+	// File lists users who are unavailable between dates (see ReadAvailabilityFile)
+	File string `toml:"file"`
+	// GitHubStatus treats users whose GitHub status is set to busy as unavailable
+	GitHubStatus bool `toml:"github_status"`
+	// Backups maps an owner to the owners who can review in their place while they are unavailable
+	Backups map[string][]string `toml:"backups"`
+}
+
+// Comments customizes the comments posted on the PR with Go text/template templates.
+// Inline templates take precedence over template files.
+type Comments struct {
+	ReviewStatusTemplate     string `toml:"review_status_template"`
+	ReviewStatusTemplateFile string `toml:"review_status_template_file"`
+	CcTemplate               string `toml:"cc_template"`
+	CcTemplateFile           string `toml:"cc_template_file"`
+	// Outdated is what happens to superseded bot comments: kept, minimized as outdated or deleted
+	Outdated string `toml:"outdated"`
+	// FoldCc lists the optional reviewers in the review status comment instead of cc comments
+	FoldCc bool `toml:"fold_cc"`
+	// InlineOwners posts an inline review comment naming the owners of every changed file, or of
+	// every directory with changed files
+	InlineOwners string `toml:"inline_owners"`
+}
+
+// ReadTemplate returns the inline template, or reads the template file (resolved like the team
+// roster) when there is no inline template.  Returns "" when neither is set.
+func ReadTemplate(inline string, path string, repoDir string, fileReader codeowners.FileReader) (string, error) {
+	if inline != "" || path == "" {
+		return inline, nil
+	}
+	file, _, err := readRepoFile(repoDir, path, fileReader, "template")
+	if err != nil {
+		return "", err
+	}
+	return string(file), nil
+}
+
+// OwnerLabels labels PRs with their owning teams
+type OwnerLabels struct {
+	Enabled bool `toml:"enabled"`
+	// Template names the label of a team with Go text/template, given .Team (e.g. "backend"),
+	// .Org and .Owner (e.g. "@org/backend")
+	Template string `toml:"template"`
+	// Color of the labels created for teams
+	Color string `toml:"color"`
 }
 
 type AdminBypass struct {
 	Enabled      bool     `toml:"enabled"`
 	AllowedUsers []string `toml:"allowed_users"`
+	// RequireReason ignores bypasses without a reason, and is on unless disabled
+	RequireReason bool `toml:"require_reason"`
+	// Labels bypass the reviews when applied by an allowed user or a repository admin
+	Labels []string `toml:"labels"`
 }
 
 func ReadConfig(path string, fileReader codeowners.FileReader) (*Config, error) {
@@ -46,12 +154,23 @@ func ReadConfig(path string, fileReader codeowners.FileReader) (*Config, error)
 		Ignore:                      []string{},
 		Enforcement:                 &Enforcement{Approval: false, FailCheck: true},
 		HighPriorityLabels:          []string{},
-		AdminBypass:                 &AdminBypass{Enabled: false, AllowedUsers: []string{}},
+		AdminBypass:                 &AdminBypass{Enabled: false, AllowedUsers: []string{}, RequireReason: true},
 		DetailedReviewers:           false,
 		SelfApprovalViaTeams:        false,
 		DisableSmartDismissal:       false,
 		RequireBothBranchReviewers:  false,
 		DisableReviewStatusComments: false,
+		ReviewRequests: &ReviewRequests{
+			Minimal:               false,
+			Prefer:                []string{PreferCurrentlyRequested},
+			AssignIndividuals:     false,
+			Assignment:            AssignmentLeastLoaded,
+			Exclude:               []string{},
+			MaxOpenReviewsPerUser: 0,
+		},
+		Availability: &Availability{File: "", GitHubStatus: false, Backups: map[string][]string{}},
+		Comments:     &Comments{Outdated: OutdatedKeep, FoldCc: false, InlineOwners: InlineOwnersOff},
+		OwnerLabels:  &OwnerLabels{Enabled: false, Template: "owner:{{.Team}}", Color: "ededed"},
 	}
 
 	// Use filesystem reader if none provided
@@ -79,5 +198,17 @@ func ReadConfig(path string, fileReader codeowners.FileReader) (*Config, error)
 	if config.AdminBypass == nil {
 		config.AdminBypass = defaultConfig.AdminBypass
 	}
+	if config.ReviewRequests == nil {
+		config.ReviewRequests = defaultConfig.ReviewRequests
+	}
+	if config.Availability == nil {
+		config.Availability = defaultConfig.Availability
+	}
+	if config.Comments == nil {
+		config.Comments = defaultConfig.Comments
+	}
+	if config.OwnerLabels == nil {
+		config.OwnerLabels = defaultConfig.OwnerLabels
+	}
 	return config, nil
 }
@@ -125,6 +125,144 @@ allow_self_approval = true
 			},
 			expectedErr: false,
 		},
+		{
+			name: "config with comment approvals enabled",
+			configContent: `
+comment_approvals = true
+`,
+			path: "testdata/",
+			expected: &Config{
+				MaxReviews:           nil,
+				MinReviews:           nil,
+				UnskippableReviewers: []string{},
+				Ignore:               []string{},
+				Enforcement:          &Enforcement{Approval: false, FailCheck: true},
+				HighPriorityLabels:   []string{},
+				CommentApprovals:     true,
+			},
+			expectedErr: false,
+		},
+		{
+			name: "config with minimal review requests",
+			configContent: `
+[review_requests]
+minimal = true
+prefer = ["individuals", "currently_requested"]
+`,
+			path: "testdata/",
+			expected: &Config{
+				MaxReviews:           nil,
+				MinReviews:           nil,
+				UnskippableReviewers: []string{},
+				Ignore:               []string{},
+				Enforcement:          &Enforcement{Approval: false, FailCheck: true},
+				HighPriorityLabels:   []string{},
+				ReviewRequests:       &ReviewRequests{Minimal: true, Prefer: []string{PreferIndividuals, PreferCurrentlyRequested}},
+			},
+			expectedErr: false,
+		},
+		{
+			name: "config with individual assignment keeps review request defaults",
+			configContent: `
+[review_requests]
+assign_individuals = true
+exclude = ["@alice"]
+max_open_reviews_per_user = 10
+`,
+			path: "testdata/",
+			expected: &Config{
+				MaxReviews:           nil,
+				MinReviews:           nil,
+				UnskippableReviewers: []string{},
+				Ignore:               []string{},
+				Enforcement:          &Enforcement{Approval: false, FailCheck: true},
+				HighPriorityLabels:   []string{},
+				ReviewRequests: &ReviewRequests{
+					Prefer:                []string{PreferCurrentlyRequested},
+					AssignIndividuals:     true,
+					Assignment:            AssignmentLeastLoaded,
+					Exclude:               []string{"@alice"},
+					MaxOpenReviewsPerUser: 10,
+				},
+			},
+			expectedErr: false,
+		},
+		{
+			name: "comments",
+			configContent: `
+[comments]
+outdated = "minimize"
+fold_cc = true
+inline_owners = "directory"
+`,
+			path: "testdata/",
+			expected: &Config{
+				MaxReviews:           nil,
+				MinReviews:           nil,
+				UnskippableReviewers: []string{},
+				Ignore:               []string{},
+				Enforcement:          &Enforcement{Approval: false, FailCheck: true},
+				HighPriorityLabels:   []string{},
+				Comments:             &Comments{Outdated: OutdatedMinimize, FoldCc: true, InlineOwners: InlineOwnersDirectory},
+			},
+			expectedErr: false,
+		},
+		{
+			name: "owner labels",
+			configContent: `
+[owner_labels]
+enabled = true
+template = "team/{{.Team}}"
+`,
+			path: "testdata/",
+			expected: &Config{
+				MaxReviews:           nil,
+				MinReviews:           nil,
+				UnskippableReviewers: []string{},
+				Ignore:               []string{},
+				Enforcement:          &Enforcement{Approval: false, FailCheck: true},
+				HighPriorityLabels:   []string{},
+				OwnerLabels:          &OwnerLabels{Enabled: true, Template: "team/{{.Team}}", Color: "ededed"},
+			},
+			expectedErr: false,
+		},
+		{
+			name: "admin bypass requires a reason by default",
+			configContent: `
+[admin_bypass]
+enabled = true
+`,
+			path: "testdata/",
+			expected: &Config{
+				MaxReviews:           nil,
+				MinReviews:           nil,
+				UnskippableReviewers: []string{},
+				Ignore:               []string{},
+				Enforcement:          &Enforcement{Approval: false, FailCheck: true},
+				HighPriorityLabels:   []string{},
+				AdminBypass:          &AdminBypass{Enabled: true, RequireReason: true},
+			},
+			expectedErr: false,
+		},
+		{
+			name: "admin bypass without a required reason",
+			configContent: `
+[admin_bypass]
+enabled = true
+require_reason = false
+`,
+			path: "testdata/",
+			expected: &Config{
+				MaxReviews:           nil,
+				MinReviews:           nil,
+				UnskippableReviewers: []string{},
+				Ignore:               []string{},
+				Enforcement:          &Enforcement{Approval: false, FailCheck: true},
+				HighPriorityLabels:   []string{},
+				AdminBypass:          &AdminBypass{Enabled: true, RequireReason: false},
+			},
+			expectedErr: false,
+		},
 		{
 			name: "invalid toml",
 			configContent: `
@@ -215,6 +353,55 @@ max_reviews = invalid
 					t.Errorf("AllowSelfApproval: expected %v, got %v", tc.expected.AllowSelfApproval, got.AllowSelfApproval)
 				}
 
+				if tc.expected.ReviewRequests != nil {
+					if got.ReviewRequests.Minimal != tc.expected.ReviewRequests.Minimal {
+						t.Errorf("ReviewRequests.Minimal: expected %v, got %v", tc.expected.ReviewRequests.Minimal, got.ReviewRequests.Minimal)
+					}
+					if !sliceEqual(got.ReviewRequests.Prefer, tc.expected.ReviewRequests.Prefer) {
+						t.Errorf("ReviewRequests.Prefer: expected %v, got %v", tc.expected.ReviewRequests.Prefer, got.ReviewRequests.Prefer)
+					}
+					if got.ReviewRequests.AssignIndividuals != tc.expected.ReviewRequests.AssignIndividuals {
+						t.Errorf("ReviewRequests.AssignIndividuals: expected %v, got %v", tc.expected.ReviewRequests.AssignIndividuals, got.ReviewRequests.AssignIndividuals)
+					}
+					if tc.expected.ReviewRequests.Assignment != "" && got.ReviewRequests.Assignment != tc.expected.ReviewRequests.Assignment {
+						t.Errorf("ReviewRequests.Assignment: expected %q, got %q", tc.expected.ReviewRequests.Assignment, got.ReviewRequests.Assignment)
+					}
+					if got.ReviewRequests.MaxOpenReviewsPerUser != tc.expected.ReviewRequests.MaxOpenReviewsPerUser {
+						t.Errorf("ReviewRequests.MaxOpenReviewsPerUser: expected %d, got %d", tc.expected.ReviewRequests.MaxOpenReviewsPerUser, got.ReviewRequests.MaxOpenReviewsPerUser)
+					}
+					if tc.expected.ReviewRequests.Exclude != nil && !sliceEqual(got.ReviewRequests.Exclude, tc.expected.ReviewRequests.Exclude) {
+						t.Errorf("ReviewRequests.Exclude: expected %v, got %v", tc.expected.ReviewRequests.Exclude, got.ReviewRequests.Exclude)
+					}
+				} else if got.ReviewRequests == nil || got.ReviewRequests.Minimal || !sliceEqual(got.ReviewRequests.Prefer, []string{PreferCurrentlyRequested}) {
+					t.Errorf("ReviewRequests: expected defaults, got %+v", got.ReviewRequests)
+				}
+
+				expectedComments := &Comments{Outdated: OutdatedKeep, InlineOwners: InlineOwnersOff}
+				if tc.expected.Comments != nil {
+					expectedComments = tc.expected.Comments
+				}
+				if got.Comments == nil || got.Comments.Outdated != expectedComments.Outdated || got.Comments.FoldCc != expectedComments.FoldCc ||
+					got.Comments.InlineOwners != expectedComments.InlineOwners {
+					t.Errorf("Comments: expected %+v, got %+v", expectedComments, got.Comments)
+				}
+
+				expectedOwnerLabels := &OwnerLabels{Template: "owner:{{.Team}}", Color: "ededed"}
+				if tc.expected.OwnerLabels != nil {
+					expectedOwnerLabels = tc.expected.OwnerLabels
+				}
+				if got.OwnerLabels == nil || *got.OwnerLabels != *expectedOwnerLabels {
+					t.Errorf("OwnerLabels: expected %+v, got %+v", expectedOwnerLabels, got.OwnerLabels)
+				}
+
+				expectedAdminBypass := &AdminBypass{RequireReason: true}
+				if tc.expected.AdminBypass != nil {
+					expectedAdminBypass = tc.expected.AdminBypass
+				}
+				if got.AdminBypass == nil || got.AdminBypass.Enabled != expectedAdminBypass.Enabled ||
+					got.AdminBypass.RequireReason != expectedAdminBypass.RequireReason {
+					t.Errorf("AdminBypass: expected %+v, got %+v", expectedAdminBypass, got.AdminBypass)
+				}
+
 				if tc.expected.Enforcement != nil {
 					if got.Enforcement == nil {
 						t.Error("expected Enforcement to be set")
@@ -234,6 +421,40 @@ max_reviews = invalid
 	}
 }
 
+func TestReadTemplate(t *testing.T) {
+	reader := &mockConfigFileReader{files: map[string]string{"repo/.github/status.tmpl": "from file"}}
+	tt := []struct {
+		name        string
+		inline      string
+		path        string
+		expected    string
+		expectedErr bool
+	}{
+		{name: "nothing configured", expected: ""},
+		{name: "inline template", inline: "inline", path: ".github/status.tmpl", expected: "inline"},
+		{name: "template file", path: ".github/status.tmpl", expected: "from file"},
+		{name: "missing template file", path: ".github/missing.tmpl", expectedErr: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ReadTemplate(tc.inline, tc.path, "repo", reader)
+			if tc.expectedErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("expected template %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
 func TestReadConfigFileError(t *testing.T) {
 	// Create a directory with no read permissions
 	testDir := t.TempDir()
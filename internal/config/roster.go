@@ -0,0 +1,134 @@
+package owners
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+)
+
+// TeamRoster maps org teams to their member logins, allowing team ownership
+// to be resolved without calling the GitHub Teams API
+type TeamRoster struct {
+	Teams map[string]RosterTeam `toml:"teams" json:"teams" yaml:"teams"`
+}
+
+// RosterTeam lists the direct members of a team and its nested (child) teams
+type RosterTeam struct {
+	Members []string `toml:"members" json:"members" yaml:"members"`
+	Teams   []string `toml:"teams" json:"teams" yaml:"teams"`
+}
+
+// ReadTeamRoster reads a team roster file in YAML, JSON or TOML format, chosen by file extension.
+// Relative paths are resolved against the repository root and read with fileReader,
+// while absolute paths (e.g. a roster downloaded by the workflow) are read from the filesystem.
+func ReadTeamRoster(repoDir string, path string, fileReader codeowners.FileReader) (*TeamRoster, error) {
+	roster := &TeamRoster{}
+	if err := readDataFile(repoDir, path, fileReader, "team roster", roster); err != nil {
+		return nil, err
+	}
+
+	// Normalize team keys so lookups are case-insensitive and tolerate a leading @
+	normalized := make(map[string]RosterTeam, len(roster.Teams))
+	for team, entry := range roster.Teams {
+		normalized[normalizeRosterTeam(team, "")] = entry
+	}
+	roster.Teams = normalized
+	return roster, nil
+}
+
+// TeamMembers returns the logins of every member of org/team, including members
+// of nested teams.  Returns false if the team is not present in the roster.
+func (r *TeamRoster) TeamMembers(org, team string) ([]string, bool) {
+	root := normalizeRosterTeam(org+"/"+team, "")
+	if _, ok := r.Teams[root]; !ok {
+		return nil, false
+	}
+
+	members := make([]string, 0)
+	seenMembers := make(map[string]bool)
+	visited := make(map[string]bool)
+	var walk func(team string)
+	walk = func(team string) {
+		// Guard against cycles in nested team definitions
+		if visited[team] {
+			return
+		}
+		visited[team] = true
+		entry, ok := r.Teams[team]
+		if !ok {
+			return
+		}
+		for _, member := range entry.Members {
+			login := strings.TrimPrefix(member, "@")
+			if seenMembers[strings.ToLower(login)] {
+				continue
+			}
+			seenMembers[strings.ToLower(login)] = true
+			members = append(members, login)
+		}
+		teamOrg, _, _ := strings.Cut(team, "/")
+		for _, child := range entry.Teams {
+			walk(normalizeRosterTeam(child, teamOrg))
+		}
+	}
+	walk(root)
+
+	slices.Sort(members)
+	return members, true
+}
+
+// normalizeRosterTeam converts a team reference to the lowercase org/team form.
+// Team references without an org are assumed to belong to defaultOrg.
+func normalizeRosterTeam(team string, defaultOrg string) string {
+	team = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(team), "@"))
+	if !strings.Contains(team, "/") && defaultOrg != "" {
+		team = defaultOrg + "/" + team
+	}
+	return team
+}
+
+// readDataFile decodes a YAML, JSON or TOML file, chosen by file extension, into v
+func readDataFile(repoDir string, path string, fileReader codeowners.FileReader, kind string, v any) error {
+	file, path, err := readRepoFile(repoDir, path, fileReader, kind)
+	if err != nil {
+		return err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(file, v)
+	case ".json":
+		err = json.Unmarshal(file, v)
+	case ".toml":
+		err = toml.Unmarshal(file, v)
+	default:
+		return fmt.Errorf("unsupported %s format: %s", kind, path)
+	}
+	if err != nil {
+		return fmt.Errorf("error parsing %s %s: %w", kind, path, err)
+	}
+	return nil
+}
+
+// readRepoFile reads a file, returning its contents and resolved path.  Relative paths are read
+// from the repository with fileReader, and absolute paths from the filesystem.
+func readRepoFile(repoDir string, path string, fileReader codeowners.FileReader, kind string) ([]byte, string, error) {
+	if fileReader == nil || filepath.IsAbs(path) {
+		fileReader = &codeowners.FilesystemReader{}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoDir, path)
+	}
+
+	if !fileReader.PathExists(path) {
+		return nil, path, fmt.Errorf("%s %s does not exist", kind, path)
+	}
+	file, err := fileReader.ReadFile(path)
+	return file, path, err
+}
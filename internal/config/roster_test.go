@@ -0,0 +1,141 @@
+package owners
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadTeamRoster(t *testing.T) {
+	tt := []struct {
+		name        string
+		path        string
+		content     string
+		expected    map[string]RosterTeam
+		expectedErr bool
+	}{
+		{
+			name: "yaml roster",
+			path: "roster.yaml",
+			content: `
+teams:
+  "@org/Backend":
+    members: [alice, bob]
+    teams: ["@org/platform"]
+`,
+			expected: map[string]RosterTeam{
+				"org/backend": {Members: []string{"alice", "bob"}, Teams: []string{"@org/platform"}},
+			},
+		},
+		{
+			name:    "json roster",
+			path:    "roster.json",
+			content: `{"teams": {"org/frontend": {"members": ["carol"]}}}`,
+			expected: map[string]RosterTeam{
+				"org/frontend": {Members: []string{"carol"}},
+			},
+		},
+		{
+			name: "toml roster",
+			path: "roster.toml",
+			content: `
+[teams."@org/data"]
+members = ["dave"]
+teams = ["analytics"]
+`,
+			expected: map[string]RosterTeam{
+				"org/data": {Members: []string{"dave"}, Teams: []string{"analytics"}},
+			},
+		},
+		{
+			name:        "unsupported extension",
+			path:        "roster.txt",
+			content:     "teams",
+			expectedErr: true,
+		},
+		{
+			name:        "invalid content",
+			path:        "roster.json",
+			content:     "{not json",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			reader := &mockConfigFileReader{files: map[string]string{"repo/" + tc.path: tc.content}}
+			roster, err := ReadTeamRoster("repo", tc.path, reader)
+			if tc.expectedErr {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(roster.Teams, tc.expected) {
+				t.Errorf("expected teams %+v, got %+v", tc.expected, roster.Teams)
+			}
+		})
+	}
+}
+
+func TestReadTeamRosterMissingFile(t *testing.T) {
+	reader := &mockConfigFileReader{files: map[string]string{}}
+	_, err := ReadTeamRoster("repo", "roster.yaml", reader)
+	if err == nil {
+		t.Error("expected error for missing roster file")
+	}
+}
+
+func TestTeamRosterTeamMembers(t *testing.T) {
+	roster := &TeamRoster{Teams: map[string]RosterTeam{
+		"org/parent":  {Members: []string{"alice", "@bob"}, Teams: []string{"@org/child", "sibling"}},
+		"org/child":   {Members: []string{"carol", "Alice"}, Teams: []string{"org/grandchild"}},
+		"org/sibling": {Members: []string{"dave"}},
+		// cycle back to the parent team
+		"org/grandchild": {Members: []string{"erin"}, Teams: []string{"org/parent"}},
+	}}
+
+	tt := []struct {
+		name          string
+		org           string
+		team          string
+		expected      []string
+		expectedFound bool
+	}{
+		{
+			name:          "nested teams are resolved recursively",
+			org:           "org",
+			team:          "parent",
+			expected:      []string{"alice", "bob", "carol", "dave", "erin"},
+			expectedFound: true,
+		},
+		{
+			name:          "lookup is case insensitive",
+			org:           "ORG",
+			team:          "Sibling",
+			expected:      []string{"dave"},
+			expectedFound: true,
+		},
+		{
+			name:          "unknown team",
+			org:           "org",
+			team:          "missing",
+			expected:      nil,
+			expectedFound: false,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			members, found := roster.TeamMembers(tc.org, tc.team)
+			if found != tc.expectedFound {
+				t.Fatalf("expected found %v, got %v", tc.expectedFound, found)
+			}
+			if !reflect.DeepEqual(members, tc.expected) {
+				t.Errorf("expected members %v, got %v", tc.expected, members)
+			}
+		})
+	}
+}
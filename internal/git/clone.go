@@ -0,0 +1,63 @@
+package git
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// BareClone is a local bare clone of a remote repository.  Diffs and files at a ref are all
+// that is needed to evaluate a PR, so no working tree is checked out.
+type BareClone struct {
+	dir string
+	url string
+}
+
+// NewBareClone creates a BareClone of the repository at url, stored in dir
+func NewBareClone(dir, url string) *BareClone {
+	return &BareClone{dir: dir, url: url}
+}
+
+// Dir is the path of the bare clone
+func (c *BareClone) Dir() string {
+	return c.dir
+}
+
+// Fetch clones the repository if it has not been cloned yet, then fetches refspecs.
+// When token is set it authenticates HTTPS requests without being stored in the clone's config.
+func (c *BareClone) Fetch(token string, refspecs ...string) error {
+	env := []string{"GIT_TERMINAL_PROMPT=0"}
+	if token != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+		env = append(env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+credentials,
+		)
+	}
+
+	if _, err := os.Stat(filepath.Join(c.dir, "HEAD")); errors.Is(err, os.ErrNotExist) {
+		parent := filepath.Dir(c.dir)
+		if err := os.MkdirAll(parent, 0755); err != nil {
+			return err
+		}
+		executor := &realGitExecutor{dir: parent, env: env}
+		if output, err := executor.execute("git", "clone", "--bare", "--quiet", c.url, c.dir); err != nil {
+			return fmt.Errorf("failed to clone %s: %w: %s", c.url, err, output)
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if len(refspecs) == 0 {
+		return nil
+	}
+	executor := &realGitExecutor{dir: c.dir, env: env}
+	args := append([]string{"fetch", "--quiet", "--force", c.url}, refspecs...)
+	if output, err := executor.execute("git", args...); err != nil {
+		return fmt.Errorf("failed to fetch %v from %s: %w: %s", refspecs, c.url, err, output)
+	}
+	return nil
+}
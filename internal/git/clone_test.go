@@ -0,0 +1,57 @@
+package git
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v: %s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func TestBareCloneFetch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	upstream := t.TempDir()
+	runGit(t, upstream, "init", "--quiet", "--initial-branch=main")
+	runGit(t, upstream, "commit", "--quiet", "--allow-empty", "-m", "base")
+	runGit(t, upstream, "checkout", "--quiet", "-b", "feature")
+	runGit(t, upstream, "commit", "--quiet", "--allow-empty", "-m", "change")
+	headSHA := runGit(t, upstream, "rev-parse", "HEAD")
+
+	clone := NewBareClone(filepath.Join(t.TempDir(), "owner", "repo.git"), upstream)
+	if err := clone.Fetch("", "+refs/heads/feature:refs/heads/feature"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if isBare := runGit(t, clone.Dir(), "rev-parse", "--is-bare-repository"); isBare != "true" {
+		t.Errorf("expected a bare repository, got %s", isBare)
+	}
+
+	// New commits are fetched into the existing clone
+	runGit(t, upstream, "commit", "--quiet", "--allow-empty", "-m", "another change")
+	newHeadSHA := runGit(t, upstream, "rev-parse", "HEAD")
+	if newHeadSHA == headSHA {
+		t.Fatal("expected a new commit")
+	}
+	if err := clone.Fetch("token", "+refs/heads/feature:refs/heads/feature"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := runGit(t, clone.Dir(), "rev-parse", "refs/heads/feature"); got != newHeadSHA {
+		t.Errorf("expected feature at %s, got %s", newHeadSHA, got)
+	}
+}
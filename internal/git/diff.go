@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"fmt"
+	"os"
 	"os/exec"
 	"slices"
 	"strings"
@@ -21,6 +22,8 @@ type gitCommandExecutor interface {
 // realGitExecutor implements GitCommandExecutor using os/exec
 type realGitExecutor struct {
 	dir string
+	// env is appended to the process environment
+	env []string
 }
 
 func newRealGitExecutor(dir string) *realGitExecutor {
@@ -30,6 +33,9 @@ func newRealGitExecutor(dir string) *realGitExecutor {
 func (e *realGitExecutor) execute(command string, args ...string) ([]byte, error) {
 	cmd := exec.Command(command, args...)
 	cmd.Dir = e.dir
+	if len(e.env) > 0 {
+		cmd.Env = append(os.Environ(), e.env...)
+	}
 	return cmd.CombinedOutput()
 }
 
@@ -0,0 +1,24 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FirstParentSubjects returns the subjects of the commits in base..head along the first parent,
+// newest first.  On a merge queue branch these are the commits the queue added for its PRs.
+func FirstParentSubjects(dir, base, head string) ([]string, error) {
+	return firstParentSubjects(base, head, newRealGitExecutor(dir))
+}
+
+func firstParentSubjects(base, head string, executor gitCommandExecutor) ([]string, error) {
+	cmdOutput, err := executor.execute("git", "log", "--first-parent", "--format=%s", fmt.Sprintf("%s..%s", base, head))
+	if err != nil {
+		return nil, fmt.Errorf("log Error: %s\n%s", err, cmdOutput)
+	}
+	subjects := make([]string, 0)
+	for line := range strings.Lines(string(cmdOutput)) {
+		subjects = append(subjects, strings.TrimSuffix(line, "\n"))
+	}
+	return subjects, nil
+}
@@ -0,0 +1,37 @@
+package git
+
+import (
+	"os/exec"
+	"slices"
+	"testing"
+)
+
+func TestFirstParentSubjects(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	runGit(t, dir, "init", "--quiet", "--initial-branch=main")
+	runGit(t, dir, "commit", "--quiet", "--allow-empty", "-m", "base")
+	baseSHA := runGit(t, dir, "rev-parse", "HEAD")
+	runGit(t, dir, "checkout", "--quiet", "-b", "feature")
+	runGit(t, dir, "commit", "--quiet", "--allow-empty", "-m", "feature change")
+	runGit(t, dir, "checkout", "--quiet", "main")
+	runGit(t, dir, "commit", "--quiet", "--allow-empty", "-m", "Add docs (#1)")
+	runGit(t, dir, "merge", "--quiet", "--no-ff", "-m", "Merge pull request #2 from org/feature", "feature")
+
+	subjects, err := FirstParentSubjects(dir, baseSHA, "HEAD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The merged branch's own commits are not on the first parent
+	expected := []string{"Merge pull request #2 from org/feature", "Add docs (#1)"}
+	if !slices.Equal(subjects, expected) {
+		t.Errorf("expected subjects %v, got %v", expected, subjects)
+	}
+
+	if _, err := FirstParentSubjects(dir, baseSHA, "missing"); err == nil {
+		t.Error("expected an error for an unknown ref")
+	}
+}
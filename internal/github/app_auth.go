@@ -0,0 +1,187 @@
+package gh
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v89/github"
+)
+
+const (
+	// GitHub rejects app JWTs valid for more than 10 minutes
+	appJWTLifetime = 9 * time.Minute
+	// Installation tokens are refreshed this long before they expire
+	installationTokenRefreshMargin = 5 * time.Minute
+)
+
+// AppCredentials identifies a GitHub App installation used to authenticate instead of a token
+type AppCredentials struct {
+	AppID int64
+	// InstallationID is optional; when 0 the installation for the repository is looked up
+	InstallationID int64
+	PrivateKey     []byte
+}
+
+// ParseAppPrivateKey parses a PEM encoded GitHub App private key (PKCS#1 or PKCS#8)
+func ParseAppPrivateKey(pemData []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, errors.New("app private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing app private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("app private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// signAppJWT creates the RS256 signed JWT used to authenticate as the app itself
+func signAppJWT(appID int64, key *rsa.PrivateKey, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]any{
+		// Backdate to allow for clock drift between the runner and GitHub
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": strconv.FormatInt(appID, 10),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// appJWTTransport authenticates requests as the GitHub App with a freshly signed JWT
+type appJWTTransport struct {
+	base  http.RoundTripper
+	appID int64
+	key   *rsa.PrivateKey
+	now   func() time.Time
+}
+
+func (t *appJWTTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	jwt, err := signAppJWT(t.appID, t.key, t.now())
+	if err != nil {
+		return nil, fmt.Errorf("error signing app JWT: %w", err)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	return t.base.RoundTrip(req)
+}
+
+// installationTokenSource exchanges the app JWT for an installation token,
+// refreshing the token shortly before it expires
+type installationTokenSource struct {
+	mu             sync.Mutex
+	apps           *github.AppsService
+	owner          string
+	repo           string
+	installationID int64
+	token          string
+	expiresAt      time.Time
+	now            func() time.Time
+}
+
+func (s *installationTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && s.now().Before(s.expiresAt.Add(-installationTokenRefreshMargin)) {
+		return s.token, nil
+	}
+
+	if s.installationID == 0 {
+		installation, _, err := s.apps.GetRepositoryInstallation(ctx, s.owner, s.repo)
+		if err != nil {
+			return "", fmt.Errorf("error finding app installation for %s/%s: %w", s.owner, s.repo, err)
+		}
+		s.installationID = installation.GetID()
+	}
+	token, _, err := s.apps.CreateInstallationToken(ctx, s.installationID, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating app installation token: %w", err)
+	}
+	s.token = token.GetToken()
+	s.expiresAt = token.GetExpiresAt().Time
+	return s.token, nil
+}
+
+// appAuthTransport authenticates requests with the current installation token
+type appAuthTransport struct {
+	base   http.RoundTripper
+	source *installationTokenSource
+}
+
+func (t *appAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
+
+// newAppAuthTransport wraps base with GitHub App installation authentication.
+// ghOptions are applied to the client used for the token exchange (e.g. API URLs).
+func newAppAuthTransport(base http.RoundTripper, owner, repo string, creds AppCredentials, ghOptions ...github.ClientOptionsFunc) (*appAuthTransport, error) {
+	key, err := ParseAppPrivateKey(creds.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	jwtTransport := &appJWTTransport{base: base, appID: creds.AppID, key: key, now: time.Now}
+	appClient, err := github.NewClient(slices.Concat(ghOptions, []github.ClientOptionsFunc{github.WithTransport(jwtTransport)})...)
+	if err != nil {
+		return nil, err
+	}
+	source := &installationTokenSource{
+		apps:           appClient.Apps,
+		owner:          owner,
+		repo:           repo,
+		installationID: creds.InstallationID,
+		now:            time.Now,
+	}
+	return &appAuthTransport{base: base, source: source}, nil
+}
+
+// AppInstallationToken creates an installation token for the app installation on owner/repo,
+// for authenticating outside of the API client (e.g. git fetches)
+func AppInstallationToken(ctx context.Context, owner, repo string, creds AppCredentials, opts ...ClientOption) (string, error) {
+	options := clientOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	transport, err := newAppAuthTransport(http.DefaultTransport, owner, repo, creds, options.enterpriseOptions()...)
+	if err != nil {
+		return "", err
+	}
+	return transport.source.Token(ctx)
+}
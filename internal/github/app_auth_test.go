@@ -0,0 +1,202 @@
+package gh
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v89/github"
+)
+
+func generateTestKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return key, pemData
+}
+
+func TestParseAppPrivateKey(t *testing.T) {
+	key, pkcs1 := generateTestKey(t)
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pkcs8 := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes})
+
+	tt := []struct {
+		name        string
+		pem         []byte
+		expectError bool
+	}{
+		{name: "pkcs1 key", pem: pkcs1, expectError: false},
+		{name: "pkcs8 key", pem: pkcs8, expectError: false},
+		{name: "not pem", pem: []byte("not a key"), expectError: true},
+		{name: "invalid key bytes", pem: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("bad")}), expectError: true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, err := ParseAppPrivateKey(tc.pem)
+			if tc.expectError {
+				if err == nil {
+					t.Error("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !parsed.Equal(key) {
+				t.Error("expected parsed key to match generated key")
+			}
+		})
+	}
+}
+
+func TestSignAppJWT(t *testing.T) {
+	key, _ := generateTestKey(t)
+	now := time.Unix(1700000000, 0)
+
+	jwt, err := signAppJWT(42, key, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 JWT parts, got %d", len(parts))
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		t.Errorf("expected valid signature: %v", err)
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var claims struct {
+		Iat int64  `json:"iat"`
+		Exp int64  `json:"exp"`
+		Iss string `json:"iss"`
+	}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.Iss != "42" {
+		t.Errorf("expected iss 42, got %s", claims.Iss)
+	}
+	if claims.Iat != now.Add(-time.Minute).Unix() {
+		t.Errorf("expected iat to be backdated one minute, got %d", claims.Iat)
+	}
+	if claims.Exp-now.Unix() > int64((10 * time.Minute).Seconds()) {
+		t.Errorf("expected exp within 10 minutes, got %d", claims.Exp)
+	}
+}
+
+func TestAppAuthTransport(t *testing.T) {
+	_, pemData := generateTestKey(t)
+	now := time.Unix(1700000000, 0)
+
+	tokenRequests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/owner/repo/installation", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer ey") {
+			t.Errorf("expected JWT authorization, got %q", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&github.Installation{ID: github.Ptr(int64(99))})
+	})
+	mux.HandleFunc("/app/installations/99/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(&github.InstallationToken{
+			Token:     github.Ptr("installation-token-" + string(rune('0'+tokenRequests))),
+			ExpiresAt: &github.Timestamp{Time: now.Add(time.Hour)},
+		})
+	})
+	var seenAuth []string
+	mux.HandleFunc("/repos/owner/repo/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		seenAuth = append(seenAuth, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&github.PullRequest{Number: github.Ptr(1)})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	baseURL := server.URL + "/"
+	urls := github.WithURLs(&baseURL, &baseURL)
+	transport, err := newAppAuthTransport(http.DefaultTransport, "owner", "repo", AppCredentials{AppID: 42, PrivateKey: pemData}, urls)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	transport.source.now = func() time.Time { return now }
+	client, err := github.NewClient(urls, github.WithTransport(transport))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	getPR := func() {
+		t.Helper()
+		if _, _, err := client.PullRequests.Get(context.Background(), "owner", "repo", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	getPR()
+	getPR()
+	// Move close enough to expiry that the token is refreshed
+	now = now.Add(56 * time.Minute)
+	getPR()
+
+	expectedAuth := []string{"Bearer installation-token-1", "Bearer installation-token-1", "Bearer installation-token-2"}
+	if strings.Join(seenAuth, ",") != strings.Join(expectedAuth, ",") {
+		t.Errorf("expected authorization headers %v, got %v", expectedAuth, seenAuth)
+	}
+	if tokenRequests != 2 {
+		t.Errorf("expected 2 token exchanges, got %d", tokenRequests)
+	}
+}
+
+func TestAppInstallationToken(t *testing.T) {
+	_, pemData := generateTestKey(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/app/installations/7/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(&github.InstallationToken{
+			Token:     github.Ptr("git-token"),
+			ExpiresAt: &github.Timestamp{Time: time.Now().Add(time.Hour)},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	creds := AppCredentials{AppID: 42, InstallationID: 7, PrivateKey: pemData}
+	token, err := AppInstallationToken(context.Background(), "owner", "repo", creds, WithEnterpriseURLs(server.URL+"/api/v3", ""))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "git-token" {
+		t.Errorf("expected git-token, got %q", token)
+	}
+}
@@ -0,0 +1,137 @@
+package gh
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v89/github"
+	f "github.com/multimediallc/codeowners-plus/pkg/functional"
+)
+
+// BypassPhrase marks a review or comment as an admin bypass (case-insensitive)
+const BypassPhrase = "codeowners bypass"
+
+// bypassScopePrefix starts a token listing the paths and owners a bypass is limited to
+const bypassScopePrefix = "scope="
+
+// Sources of bypasses in Bypass.Source
+const (
+	BypassSourceReview  = "review"
+	BypassSourceComment = "comment"
+	BypassSourceLabel   = "label"
+)
+
+// Bypass is an authorized bypass of the codeowners reviews
+type Bypass struct {
+	User   string `json:"user"`
+	Reason string `json:"reason"`
+	// Scope limits the bypass to these paths and owners, a full bypass when empty
+	Scope     []string  `json:"scope,omitempty"`
+	Source    string    `json:"source"`
+	URL       string    `json:"url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ParseBypass reads the bypass on the first line of text containing the bypass phrase.  The rest
+// of that line is the reason, apart from `scope=<path or owner>[,...]` tokens which limit the bypass.
+func ParseBypass(text string) (reason string, scope []string, found bool) {
+	for line := range strings.Lines(text) {
+		index := indexFold(line, BypassPhrase)
+		if index < 0 {
+			continue
+		}
+		reason, scope = ParseBypassArgs(strings.Fields(line[index+len(BypassPhrase):]))
+		return reason, scope, true
+	}
+	return "", nil, false
+}
+
+// ParseBypassArgs returns the reason and scope of the words following the bypass phrase
+func ParseBypassArgs(args []string) (reason string, scope []string) {
+	words := make([]string, 0)
+	for _, field := range args {
+		if items, isScope := strings.CutPrefix(field, bypassScopePrefix); isScope {
+			for item := range strings.SplitSeq(strings.TrimSuffix(items, ":"), ",") {
+				if item != "" {
+					scope = append(scope, item)
+				}
+			}
+			continue
+		}
+		words = append(words, field)
+	}
+	return strings.TrimLeft(strings.Join(words, " "), ":-–— "), scope
+}
+
+// bypassLabels returns the bypasses of the bypass labels on the PR, which count when their last
+// "labeled" timeline event is from a user in allowedUsers or a repository admin
+func (gh *GHClient) bypassLabels(labels []string, allowedUsers []string) ([]*Bypass, error) {
+	applied := f.Filtered(labels, func(label string) bool {
+		return slices.ContainsFunc(gh.pr.Labels, func(prLabel *github.Label) bool { return strings.EqualFold(prLabel.GetName(), label) })
+	})
+	if len(applied) == 0 {
+		return nil, nil
+	}
+	events, err := gh.labeledEvents()
+	if err != nil {
+		return nil, err
+	}
+
+	bypasses := make([]*Bypass, 0)
+	for _, label := range applied {
+		var labeled *github.Timeline
+		for _, event := range events {
+			if strings.EqualFold(event.GetLabel().GetName(), label) {
+				labeled = event
+			}
+		}
+		if labeled == nil {
+			continue
+		}
+		actor := labeled.GetActor().GetLogin()
+		if gh.isBypassAllowed(actor, allowedUsers) {
+			bypasses = append(bypasses, &Bypass{
+				User:      actor,
+				Reason:    fmt.Sprintf("labeled %s", labeled.GetLabel().GetName()),
+				Source:    BypassSourceLabel,
+				CreatedAt: labeled.GetCreatedAt().Time,
+			})
+		} else {
+			_, _ = fmt.Fprintf(gh.warningBuffer, "Warning: Ignoring bypass label %s applied by %s, who is not allowed to bypass\n", label, actor)
+		}
+	}
+	return bypasses, nil
+}
+
+// labeledEvents lists the "labeled" events of the PR timeline, oldest first
+func (gh *GHClient) labeledEvents() ([]*github.Timeline, error) {
+	allEvents := make([]*github.Timeline, 0)
+	listEvents := func(page int) (*github.Response, error) {
+		listOptions := &github.ListOptions{PerPage: 100, Page: page}
+		events, res, err := gh.client.Issues.ListIssueTimeline(gh.ctx, gh.owner, gh.repo, gh.pr.GetNumber(), listOptions)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = res.Body.Close()
+		}()
+		allEvents = append(allEvents, events...)
+		return res, err
+	}
+	if err := walkPaginatedApi(listEvents); err != nil {
+		return nil, err
+	}
+	return f.Filtered(allEvents, func(event *github.Timeline) bool { return event.GetEvent() == "labeled" }), nil
+}
+
+// indexFold is the index of the first case-insensitive match of the ASCII substr in s, or -1
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,143 @@
+package gh
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v89/github"
+)
+
+func TestParseBypass(t *testing.T) {
+	tt := []struct {
+		name           string
+		text           string
+		expectedReason string
+		expectedScope  []string
+		expectedFound  bool
+	}{
+		{name: "phrase only", text: "Codeowners Bypass", expectedFound: true},
+		{name: "reason", text: "CODEOWNERS BYPASS: production outage INC-42", expectedReason: "production outage INC-42", expectedFound: true},
+		{name: "reason after other text", text: "🔓 Codeowners Bypass - hotfix", expectedReason: "hotfix", expectedFound: true},
+		{
+			name:           "scope",
+			text:           "Thanks\ncodeowners bypass scope=docs/,@org/docs scope=README.md typo fix\nmore details",
+			expectedReason: "typo fix",
+			expectedScope:  []string{"docs/", "@org/docs", "README.md"},
+			expectedFound:  true,
+		},
+		{name: "slash command", text: "/codeowners bypass scope=docs: release", expectedReason: "release", expectedScope: []string{"docs"}, expectedFound: true},
+		{name: "no phrase", text: "LGTM, bypassing nothing"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			reason, scope, found := ParseBypass(tc.text)
+			if reason != tc.expectedReason || !slices.Equal(scope, tc.expectedScope) || found != tc.expectedFound {
+				t.Errorf("expected (%q, %v, %t), got (%q, %v, %t)", tc.expectedReason, tc.expectedScope, tc.expectedFound, reason, scope, found)
+			}
+		})
+	}
+}
+
+func TestFindBypassApprovalsRecord(t *testing.T) {
+	submitted := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	gh := &GHClient{
+		pr: &github.PullRequest{Number: github.Ptr(123)},
+		reviews: []*github.PullRequestReview{{
+			ID:          github.Ptr[int64](1),
+			Body:        github.Ptr("Codeowners Bypass scope=docs/ typo fix"),
+			User:        &github.User{Login: github.Ptr("Release-Manager")},
+			HTMLURL:     github.Ptr("https://github.com/org/repo/pull/123#pullrequestreview-1"),
+			SubmittedAt: &github.Timestamp{Time: submitted},
+		}},
+	}
+
+	bypasses, err := gh.FindBypassApprovals([]string{"release-manager"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Bypass{
+		User:      "Release-Manager",
+		Reason:    "typo fix",
+		Scope:     []string{"docs/"},
+		Source:    BypassSourceReview,
+		URL:       "https://github.com/org/repo/pull/123#pullrequestreview-1",
+		CreatedAt: submitted,
+	}
+	if len(bypasses) != 1 || !reflect.DeepEqual(*bypasses[0], expected) {
+		t.Errorf("expected bypass %+v, got %v", expected, bypasses)
+	}
+}
+
+func TestFindBypassApprovalsLabels(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	labeledAt := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	labeled := func(label, actor string, created time.Time) *github.Timeline {
+		return &github.Timeline{
+			Event:     github.Ptr("labeled"),
+			Label:     &github.Label{Name: github.Ptr(label)},
+			Actor:     &github.User{Login: github.Ptr(actor)},
+			CreatedAt: &github.Timestamp{Time: created},
+		}
+	}
+	gh.pr = &github.PullRequest{
+		Number: github.Ptr(123),
+		Labels: []*github.Label{{Name: github.Ptr("Emergency")}, {Name: github.Ptr("hotfix")}, {Name: github.Ptr("bug")}},
+	}
+	gh.reviews = []*github.PullRequestReview{}
+	warnings := &bytes.Buffer{}
+	gh.warningBuffer = warnings
+	mux.HandleFunc("/repos/test-owner/test-repo/issues/123/timeline", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]*github.Timeline{
+			labeled("emergency", "developer", labeledAt.Add(-time.Hour)),
+			{Event: github.Ptr("unlabeled"), Label: &github.Label{Name: github.Ptr("emergency")}, Actor: &github.User{Login: github.Ptr("developer")}},
+			labeled("emergency", "Release-Manager", labeledAt),
+			labeled("hotfix", "developer", labeledAt),
+			labeled("bug", "developer", labeledAt),
+		})
+	})
+	mux.HandleFunc("/repos/test-owner/test-repo/collaborators/developer/permission", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"permission": "write"})
+	})
+
+	bypasses, err := gh.FindBypassApprovals([]string{"release-manager"}, []string{"emergency", "hotfix", "skip-review"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := Bypass{
+		User:      "Release-Manager",
+		Reason:    "labeled emergency",
+		Source:    BypassSourceLabel,
+		CreatedAt: labeledAt,
+	}
+	if len(bypasses) != 1 || !reflect.DeepEqual(*bypasses[0], expected) {
+		t.Errorf("expected bypass %+v, got %v", expected, bypasses)
+	}
+	if !strings.Contains(warnings.String(), "hotfix applied by developer") {
+		t.Errorf("expected a warning for the unauthorized label, got %q", warnings.String())
+	}
+}
+
+func TestFindBypassApprovalsLabelsNotApplied(t *testing.T) {
+	gh := &GHClient{
+		pr:      &github.PullRequest{Number: github.Ptr(123), Labels: []*github.Label{{Name: github.Ptr("bug")}}},
+		reviews: []*github.PullRequestReview{},
+	}
+
+	// No timeline request is made without a bypass label on the PR
+	bypasses, err := gh.FindBypassApprovals(nil, []string{"emergency"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(bypasses) != 0 {
+		t.Errorf("expected no bypasses, got %v", bypasses)
+	}
+}
@@ -0,0 +1,227 @@
+package gh
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a cached GitHub API response body along with the headers needed
+// to replay it (pagination links) and revalidate it (ETag)
+type CacheEntry struct {
+	Body     []byte    `json:"body"`
+	ETag     string    `json:"etag,omitempty"`
+	Link     string    `json:"link,omitempty"`
+	StoredAt time.Time `json:"stored_at"`
+}
+
+// Cache stores GitHub API responses for team membership and repository permission lookups
+type Cache interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry) error
+}
+
+// MemoryCache is a Cache which lives for the lifetime of the process
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]*CacheEntry
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]*CacheEntry)}
+}
+
+func (c *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *MemoryCache) Set(key string, entry *CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+	return nil
+}
+
+// FileCache is a Cache persisted as a JSON file, so it can be shared between runs
+// (for example with actions/cache)
+type FileCache struct {
+	path   string
+	memory *MemoryCache
+	// writeMu serializes writes of the file, since the cache may be shared by concurrent evaluations
+	writeMu sync.Mutex
+}
+
+// NewFileCache loads the cache file at path.  A missing file results in an empty cache.
+func NewFileCache(path string) (*FileCache, error) {
+	cache := &FileCache{path: path, memory: NewMemoryCache()}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading cache file %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &cache.memory.entries); err != nil {
+		return nil, fmt.Errorf("error parsing cache file %s: %w", path, err)
+	}
+	if cache.memory.entries == nil {
+		cache.memory.entries = make(map[string]*CacheEntry)
+	}
+	return cache, nil
+}
+
+func (c *FileCache) Get(key string) (*CacheEntry, bool) {
+	return c.memory.Get(key)
+}
+
+// Set stores the entry and writes the whole cache back to disk
+func (c *FileCache) Set(key string, entry *CacheEntry) error {
+	if err := c.memory.Set(key, entry); err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.memory.mu.Lock()
+	data, err := json.Marshal(c.memory.entries)
+	c.memory.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so an interrupted run cannot corrupt the cache
+	tmpPath := c.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, c.path)
+}
+
+// cacheablePaths are the API endpoints whose responses change rarely enough to cache:
+// team members and child teams
+var cacheablePaths = []*regexp.Regexp{
+	regexp.MustCompile(`/orgs/[^/]+/teams/[^/]+/members$`),
+	regexp.MustCompile(`/orgs/[^/]+/teams/[^/]+/teams$`),
+}
+
+// revalidatedPaths are cached but revalidated on every use, since they decide who may bypass reviews
+// and a revoked permission must apply immediately: repository permission levels
+var revalidatedPaths = []*regexp.Regexp{
+	regexp.MustCompile(`/repos/[^/]+/[^/]+/collaborators/[^/]+/permission$`),
+}
+
+func isCacheable(req *http.Request) bool {
+	return matchesPath(req, cacheablePaths)
+}
+
+func isRevalidated(req *http.Request) bool {
+	return matchesPath(req, revalidatedPaths)
+}
+
+func matchesPath(req *http.Request, paths []*regexp.Regexp) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+	for _, path := range paths {
+		if path.MatchString(req.URL.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// cachingTransport serves cacheable GET requests from the cache while they are younger than ttl.
+// Expired entries, and entries of revalidated paths, are revalidated with an ETag conditional request, and a 304 Not Modified
+// response (which does not count against the rate limit) refreshes the cached entry.
+type cachingTransport struct {
+	base          http.RoundTripper
+	cache         Cache
+	ttl           time.Duration
+	now           func() time.Time
+	warningWriter io.Writer
+}
+
+func newCachingTransport(base http.RoundTripper, cache Cache, ttl time.Duration) *cachingTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &cachingTransport{base, cache, ttl, time.Now, io.Discard}
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	revalidate := isRevalidated(req)
+	if !revalidate && !isCacheable(req) {
+		return t.base.RoundTrip(req)
+	}
+	key := req.URL.String()
+	entry, found := t.cache.Get(key)
+	if found && !revalidate && t.now().Sub(entry.StoredAt) < t.ttl {
+		return entry.response(req), nil
+	}
+
+	if found && entry.ETag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && found:
+		_ = res.Body.Close()
+		entry = &CacheEntry{Body: entry.Body, ETag: entry.ETag, Link: entry.Link, StoredAt: t.now()}
+		t.store(key, entry)
+		return entry.response(req), nil
+	case res.StatusCode == http.StatusOK:
+		body, err := io.ReadAll(res.Body)
+		_ = res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		t.store(key, &CacheEntry{Body: body, ETag: res.Header.Get("ETag"), Link: res.Header.Get("Link"), StoredAt: t.now()})
+	}
+	return res, nil
+}
+
+func (t *cachingTransport) store(key string, entry *CacheEntry) {
+	if err := t.cache.Set(key, entry); err != nil {
+		_, _ = fmt.Fprintf(t.warningWriter, "WARNING: Error writing API cache: %v\n", err)
+	}
+}
+
+// response replays the cached entry as a 200 OK response to req
+func (e *CacheEntry) response(req *http.Request) *http.Response {
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	if e.ETag != "" {
+		header.Set("ETag", e.ETag)
+	}
+	if e.Link != "" {
+		header.Set("Link", e.Link)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
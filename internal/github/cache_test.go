@@ -0,0 +1,144 @@
+package gh
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v89/github"
+)
+
+func TestFileCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "api.json")
+
+	cache, err := NewFileCache(path)
+	if err != nil {
+		t.Fatalf("unexpected error for missing cache file: %v", err)
+	}
+	if _, found := cache.Get("key"); found {
+		t.Error("expected empty cache")
+	}
+
+	expected := &CacheEntry{Body: []byte(`[{"login":"user1"}]`), ETag: `"abc"`, StoredAt: time.Unix(100, 0).UTC()}
+	if err := cache.Set("key", expected); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reloaded, err := NewFileCache(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entry, found := reloaded.Get("key")
+	if !found {
+		t.Fatal("expected entry to be persisted")
+	}
+	if string(entry.Body) != string(expected.Body) || entry.ETag != expected.ETag || !entry.StoredAt.Equal(expected.StoredAt) {
+		t.Errorf("expected entry %+v, got %+v", expected, entry)
+	}
+}
+
+func TestCachingTransport(t *testing.T) {
+	requests := 0
+	conditionalRequests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/org/teams/team/members", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditionalRequests++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		_ = json.NewEncoder(w).Encode([]*github.User{{Login: github.Ptr("member1")}})
+	})
+	mux.HandleFunc("/repos/org/repo/collaborators/admin1/permission", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"p1"` {
+			conditionalRequests++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"p1"`)
+		_ = json.NewEncoder(w).Encode(&github.RepositoryPermissionLevel{Permission: github.Ptr("admin")})
+	})
+	mux.HandleFunc("/repos/org/repo/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&github.PullRequest{Number: github.Ptr(1)})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	now := time.Unix(1000, 0)
+	transport := newCachingTransport(http.DefaultTransport, NewMemoryCache(), time.Hour)
+	transport.now = func() time.Time { return now }
+	baseURL := server.URL + "/"
+	client, err := github.NewClient(github.WithURLs(&baseURL, &baseURL), github.WithTransport(transport))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	listMembers := func() []*github.User {
+		t.Helper()
+		users, _, err := client.Teams.ListTeamMembersBySlug(context.Background(), "org", "team", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return users
+	}
+
+	tt := []struct {
+		name                        string
+		advance                     time.Duration
+		expectedRequests            int
+		expectedConditionalRequests int
+	}{
+		{name: "first request hits the API", advance: 0, expectedRequests: 1, expectedConditionalRequests: 0},
+		{name: "fresh entry is served from cache", advance: 30 * time.Minute, expectedRequests: 1, expectedConditionalRequests: 0},
+		{name: "expired entry is revalidated with ETag", advance: time.Hour, expectedRequests: 2, expectedConditionalRequests: 1},
+		{name: "revalidated entry is fresh again", advance: 10 * time.Minute, expectedRequests: 2, expectedConditionalRequests: 1},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			now = now.Add(tc.advance)
+			users := listMembers()
+			if len(users) != 1 || users[0].GetLogin() != "member1" {
+				t.Errorf("expected [member1], got %v", users)
+			}
+			if requests != tc.expectedRequests {
+				t.Errorf("expected %d requests, got %d", tc.expectedRequests, requests)
+			}
+			if conditionalRequests != tc.expectedConditionalRequests {
+				t.Errorf("expected %d conditional requests, got %d", tc.expectedConditionalRequests, conditionalRequests)
+			}
+		})
+	}
+
+	// Permission lookups are revalidated on every use, even while fresh
+	requests, conditionalRequests = 0, 0
+	for range 2 {
+		if _, _, err := client.Repositories.GetPermissionLevel(context.Background(), "org", "repo", "admin1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if requests != 2 || conditionalRequests != 1 {
+		t.Errorf("expected 2 requests with 1 conditional, got %d with %d conditional", requests, conditionalRequests)
+	}
+
+	// Endpoints other than team membership and permissions are never cached
+	requests = 0
+	for range 2 {
+		if _, _, err := client.PullRequests.Get(context.Background(), "org", "repo", 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("expected uncacheable requests to always hit the API, got %d requests", requests)
+	}
+}
@@ -0,0 +1,154 @@
+package gh
+
+import (
+	"time"
+
+	"github.com/google/go-github/v89/github"
+)
+
+// The Checks API accepts at most 50 annotations per request
+const maxAnnotationsPerRequest = 50
+
+const (
+	AnnotationLevelFailure = "failure"
+	AnnotationLevelWarning = "warning"
+)
+
+// CheckRunAnnotation marks a file (at a line) in the check run output
+type CheckRunAnnotation struct {
+	Path    string
+	Line    int
+	Level   string
+	Title   string
+	Message string
+}
+
+// CheckRunResult is the outcome of a codeowners evaluation reported as a check run
+type CheckRunResult struct {
+	Success     bool
+	Title       string
+	Summary     string
+	Text        string
+	Annotations []CheckRunAnnotation
+}
+
+// CreateCheckRun reports the result as a completed check run on the PR head commit (or the SetStatusSHA commit).
+// The Checks API appends annotations on update, so a new check run is created for results with annotations
+// and GitHub shows the most recent one.  A result without annotations updates the check run with the same
+// name on the commit in place, as long as it has no annotations either.
+func (gh *GHClient) CreateCheckRun(name string, result CheckRunResult) error {
+	if gh.pr == nil {
+		return &NoPRError{}
+	}
+
+	conclusion := "failure"
+	if result.Success {
+		conclusion = "success"
+	}
+	batches := annotationBatches(result.Annotations)
+	output := func(annotations []*github.CheckRunAnnotation) *github.CheckRunOutput {
+		return &github.CheckRunOutput{
+			Title:       github.Ptr(result.Title),
+			Summary:     github.Ptr(result.Summary),
+			Text:        github.Ptr(result.Text),
+			Annotations: annotations,
+		}
+	}
+
+	var checkRunID int64
+	found := false
+	if len(result.Annotations) == 0 {
+		var err error
+		checkRunID, found, err = gh.findUnannotatedCheckRun(name)
+		if err != nil {
+			return err
+		}
+	}
+	now := &github.Timestamp{Time: time.Now()}
+	if found {
+		_, res, err := gh.client.Checks.UpdateCheckRun(gh.ctx, gh.owner, gh.repo, checkRunID, github.UpdateCheckRunOptions{
+			Name:        name,
+			Status:      github.Ptr("completed"),
+			Conclusion:  github.Ptr(conclusion),
+			CompletedAt: now,
+			Output:      output(batches[0]),
+		})
+		if err != nil {
+			return err
+		}
+		_ = res.Body.Close()
+	} else {
+		checkRun, res, err := gh.client.Checks.CreateCheckRun(gh.ctx, gh.owner, gh.repo, github.CreateCheckRunOptions{
+			Name:        name,
+			HeadSHA:     gh.reportSHA(),
+			Status:      github.Ptr("completed"),
+			Conclusion:  github.Ptr(conclusion),
+			CompletedAt: now,
+			Output:      output(batches[0]),
+		})
+		if err != nil {
+			return err
+		}
+		_ = res.Body.Close()
+		checkRunID = checkRun.GetID()
+	}
+
+	// Annotations beyond the first batch are appended by updating the check run
+	for _, batch := range batches[1:] {
+		_, res, err := gh.client.Checks.UpdateCheckRun(gh.ctx, gh.owner, gh.repo, checkRunID, github.UpdateCheckRunOptions{
+			Name:   name,
+			Output: output(batch),
+		})
+		if err != nil {
+			return err
+		}
+		_ = res.Body.Close()
+	}
+	return nil
+}
+
+// findUnannotatedCheckRun returns the ID of the most recent check run with the name on the reported commit,
+// if there is one and it has no annotations
+func (gh *GHClient) findUnannotatedCheckRun(name string) (int64, bool, error) {
+	checkRuns, res, err := gh.client.Checks.ListCheckRunsForRef(gh.ctx, gh.owner, gh.repo, gh.reportSHA(), &github.ListCheckRunsOptions{
+		CheckName: github.Ptr(name),
+		Filter:    github.Ptr("latest"),
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	for _, checkRun := range checkRuns.CheckRuns {
+		if checkRun.GetName() == name {
+			if checkRun.GetOutput().GetAnnotationsCount() > 0 {
+				return 0, false, nil
+			}
+			return checkRun.GetID(), true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// annotationBatches splits annotations into request sized batches, always returning at least one batch
+func annotationBatches(annotations []CheckRunAnnotation) [][]*github.CheckRunAnnotation {
+	batches := [][]*github.CheckRunAnnotation{{}}
+	for _, annotation := range annotations {
+		last := len(batches) - 1
+		if len(batches[last]) == maxAnnotationsPerRequest {
+			batches = append(batches, []*github.CheckRunAnnotation{})
+			last++
+		}
+		line := max(annotation.Line, 1)
+		batches[last] = append(batches[last], &github.CheckRunAnnotation{
+			Path:            github.Ptr(annotation.Path),
+			StartLine:       github.Ptr(line),
+			EndLine:         github.Ptr(line),
+			AnnotationLevel: github.Ptr(annotation.Level),
+			Title:           github.Ptr(annotation.Title),
+			Message:         github.Ptr(annotation.Message),
+		})
+	}
+	return batches
+}
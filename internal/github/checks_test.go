@@ -0,0 +1,172 @@
+package gh
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v89/github"
+)
+
+func TestCreateCheckRun(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+	gh.pr = &github.PullRequest{Number: github.Ptr(1), Head: &github.PullRequestBranch{SHA: github.Ptr("abc123")}}
+
+	annotations := make([]CheckRunAnnotation, 0, 120)
+	for i := range 120 {
+		annotations = append(annotations, CheckRunAnnotation{
+			Path:    fmt.Sprintf("file%d.go", i),
+			Level:   AnnotationLevelFailure,
+			Title:   "Codeowners approval required",
+			Message: "Requires approval from:\n- @user1",
+		})
+	}
+
+	// Results with annotations always create a new check run, so earlier annotations are not kept
+	mux.HandleFunc("/repos/test-owner/test-repo/commits/abc123/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected no lookup of existing check runs for a result with annotations")
+	})
+	var created github.CreateCheckRunOptions
+	mux.HandleFunc("/repos/test-owner/test-repo/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+			t.Fatalf("error decoding request: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(&github.CheckRun{ID: github.Ptr(int64(42))})
+	})
+	updatedBatches := []int{}
+	mux.HandleFunc("/repos/test-owner/test-repo/check-runs/42", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("expected PATCH, got %s", r.Method)
+		}
+		var updated github.UpdateCheckRunOptions
+		if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+			t.Fatalf("error decoding request: %v", err)
+		}
+		updatedBatches = append(updatedBatches, len(updated.Output.Annotations))
+		_ = json.NewEncoder(w).Encode(&github.CheckRun{ID: github.Ptr(int64(42))})
+	})
+
+	err := gh.CreateCheckRun("Codeowners Plus", CheckRunResult{
+		Success:     false,
+		Title:       "Codeowners reviews required",
+		Summary:     "Missing approvals",
+		Annotations: annotations,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if created.Name != "Codeowners Plus" || created.HeadSHA != "abc123" {
+		t.Errorf("expected check run Codeowners Plus on abc123, got %s on %s", created.Name, created.HeadSHA)
+	}
+	if created.GetConclusion() != "failure" || created.GetStatus() != "completed" {
+		t.Errorf("expected completed failure, got %s %s", created.GetStatus(), created.GetConclusion())
+	}
+	if len(created.Output.Annotations) != maxAnnotationsPerRequest {
+		t.Errorf("expected %d annotations on create, got %d", maxAnnotationsPerRequest, len(created.Output.Annotations))
+	}
+	if created.Output.Annotations[0].GetStartLine() != 1 {
+		t.Errorf("expected annotation line to default to 1, got %d", created.Output.Annotations[0].GetStartLine())
+	}
+	expectedBatches := []int{50, 20}
+	if fmt.Sprint(updatedBatches) != fmt.Sprint(expectedBatches) {
+		t.Errorf("expected update batches %v, got %v", expectedBatches, updatedBatches)
+	}
+}
+
+func TestCreateCheckRunUpdatesExisting(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+	gh.pr = &github.PullRequest{Number: github.Ptr(1), Head: &github.PullRequestBranch{SHA: github.Ptr("abc123")}}
+
+	mux.HandleFunc("/repos/test-owner/test-repo/commits/abc123/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		if name := r.URL.Query().Get("check_name"); name != "Codeowners Plus" {
+			t.Errorf("expected check runs filtered by name, got %q", name)
+		}
+		_ = json.NewEncoder(w).Encode(&github.ListCheckRunsResults{
+			Total: github.Ptr(1),
+			CheckRuns: []*github.CheckRun{{
+				ID:     github.Ptr(int64(7)),
+				Name:   github.Ptr("Codeowners Plus"),
+				Output: &github.CheckRunOutput{AnnotationsCount: github.Ptr(0)},
+			}},
+		})
+	})
+	mux.HandleFunc("/repos/test-owner/test-repo/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected the existing check run to be updated, got a new check run")
+	})
+	var updated github.UpdateCheckRunOptions
+	mux.HandleFunc("/repos/test-owner/test-repo/check-runs/7", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("expected PATCH, got %s", r.Method)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+			t.Fatalf("error decoding request: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(&github.CheckRun{ID: github.Ptr(int64(7))})
+	})
+
+	err := gh.CreateCheckRun("Codeowners Plus", CheckRunResult{
+		Success: true,
+		Title:   "Codeowners reviews satisfied",
+		Summary: "All required approvals were given",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if updated.GetConclusion() != "success" || updated.GetStatus() != "completed" {
+		t.Errorf("expected completed success, got %s %s", updated.GetStatus(), updated.GetConclusion())
+	}
+	if updated.Output.GetTitle() != "Codeowners reviews satisfied" {
+		t.Errorf("expected the new result, got %s", updated.Output.GetTitle())
+	}
+}
+
+func TestCreateCheckRunReplacesAnnotated(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+	gh.pr = &github.PullRequest{Number: github.Ptr(1), Head: &github.PullRequestBranch{SHA: github.Ptr("abc123")}}
+
+	mux.HandleFunc("/repos/test-owner/test-repo/commits/abc123/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(&github.ListCheckRunsResults{
+			Total: github.Ptr(1),
+			CheckRuns: []*github.CheckRun{{
+				ID:     github.Ptr(int64(7)),
+				Name:   github.Ptr("Codeowners Plus"),
+				Output: &github.CheckRunOutput{AnnotationsCount: github.Ptr(3)},
+			}},
+		})
+	})
+	mux.HandleFunc("/repos/test-owner/test-repo/check-runs/7", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected a check run with annotations not to be updated")
+	})
+	var created github.CreateCheckRunOptions
+	mux.HandleFunc("/repos/test-owner/test-repo/check-runs", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+			t.Fatalf("error decoding request: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(&github.CheckRun{ID: github.Ptr(int64(8))})
+	})
+
+	err := gh.CreateCheckRun("Codeowners Plus", CheckRunResult{Success: true, Title: "Codeowners reviews satisfied"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.GetConclusion() != "success" || created.HeadSHA != "abc123" {
+		t.Errorf("expected a new successful check run on abc123, got %s on %s", created.GetConclusion(), created.HeadSHA)
+	}
+}
+
+func TestCreateCheckRunNoPR(t *testing.T) {
+	gh := &GHClient{}
+	err := gh.CreateCheckRun("Codeowners Plus", CheckRunResult{})
+	if _, ok := err.(*NoPRError); !ok {
+		t.Errorf("expected NoPRError, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package gh
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v89/github"
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+)
+
+// ApproveCommand approves the PR from a comment, and `/approve cancel` withdraws the approval
+const ApproveCommand = "/approve"
+
+// ParseApproveCommand reads the `/approve` or `/approve cancel` lines of a comment.  The last one
+// wins, and found is false when the comment has neither.
+func ParseApproveCommand(body string) (approve bool, found bool) {
+	for line := range strings.Lines(body) {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || fields[0] != ApproveCommand {
+			continue
+		}
+		found = true
+		approve = len(fields) == 1 || fields[1] != "cancel"
+	}
+	return approve, found
+}
+
+// approvalRecordPrefix starts the bot comment recording the PR head an `/approve` comment approved
+const approvalRecordPrefix = "<!-- codeowners-plus:approval "
+
+// ApprovalRecord is the hidden first line of the bot comment recording that sha was the PR head when
+// the `/approve` comment commentID was handled
+func ApprovalRecord(commentID int64, sha string) string {
+	return fmt.Sprintf("%scomment=%d sha=%s -->", approvalRecordPrefix, commentID, sha)
+}
+
+// parseApprovalRecord reads the comment ID and PR head of an ApprovalRecord line
+func parseApprovalRecord(body string) (commentID int64, sha string, ok bool) {
+	line, _, _ := strings.Cut(body, "\n")
+	if _, err := fmt.Sscanf(line, approvalRecordPrefix+"comment=%d sha=%s -->", &commentID, &sha); err != nil {
+		return 0, "", false
+	}
+	return commentID, sha, sha != ""
+}
+
+// GetCommentApprovals returns the approvals of owners from `/approve` comments which were not
+// cancelled by a later `/approve cancel`.  The commit of an approval is the PR head recorded by the
+// bot when it handled the comment, since commit dates are set by the commit author and cannot tell
+// which commits were pushed before the comment.  Approvals without a record do not count.
+func (gh *GHClient) GetCommentApprovals() ([]*CurrentApproval, error) {
+	if gh.pr == nil {
+		return nil, &NoPRError{}
+	}
+	if gh.userReviewerMap == nil {
+		return nil, &UserReviewerMapNotInitError{}
+	}
+	records, err := gh.FindOwnComments([]string{approvalRecordPrefix})
+	if err != nil {
+		return nil, err
+	}
+	heads := make(map[int64]string)
+	for _, record := range records {
+		if commentID, sha, ok := parseApprovalRecord(record.GetBody()); ok {
+			heads[commentID] = sha
+		}
+	}
+	return commentApprovals(gh.comments, heads, gh.pr.GetUser().GetLogin(), gh.userReviewerMap), nil
+}
+
+// commentApprovals keeps the last `/approve` command of every owner, ignoring the PR author.  An
+// approval is attached to the PR head recorded for its comment in heads, and approvals without one
+// are left out.
+func commentApprovals(comments []*github.IssueComment, heads map[int64]string, author string, userReviewerMap ghUserReviewerMap) []*CurrentApproval {
+	latest := make(map[string]*github.IssueComment)
+	users := make([]string, 0)
+	for _, comment := range comments {
+		login := comment.GetUser().GetLogin()
+		if strings.EqualFold(login, author) {
+			continue
+		}
+		approve, found := ParseApproveCommand(comment.GetBody())
+		if !found {
+			continue
+		}
+		user := strings.ToLower(login)
+		if !slices.Contains(users, user) {
+			users = append(users, user)
+		}
+		if approve {
+			latest[user] = comment
+		} else {
+			latest[user] = nil
+		}
+	}
+
+	approvals := make([]*CurrentApproval, 0, len(users))
+	for _, user := range users {
+		comment := latest[user]
+		reviewers, isOwner := userReviewerMap[user]
+		if comment == nil || !isOwner {
+			continue
+		}
+		commitID := heads[comment.GetID()]
+		if commitID == "" {
+			continue
+		}
+		approvals = append(approvals, &CurrentApproval{
+			GHLogin:   codeowners.NewSlug(comment.GetUser().GetLogin()),
+			Reviewers: reviewers,
+			CommitID:  commitID,
+			CommentID: comment.GetID(),
+		})
+	}
+	return approvals
+}
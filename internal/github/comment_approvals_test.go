@@ -0,0 +1,124 @@
+package gh
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v89/github"
+	"github.com/multimediallc/codeowners-plus/pkg/codeowners"
+)
+
+func TestParseApproveCommand(t *testing.T) {
+	tt := []struct {
+		name            string
+		body            string
+		expectedApprove bool
+		expectedFound   bool
+	}{
+		{name: "approve", body: "/approve", expectedApprove: true, expectedFound: true},
+		{name: "approve with text", body: "Looks good\n/approve\nthanks", expectedApprove: true, expectedFound: true},
+		{name: "cancel", body: "/approve cancel", expectedFound: true},
+		{name: "last command wins", body: "/approve cancel\n/approve", expectedApprove: true, expectedFound: true},
+		{name: "quoted command", body: "> /approve\nwhy?"},
+		{name: "other command", body: "/approved"},
+		{name: "no command", body: "LGTM"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			approve, found := ParseApproveCommand(tc.body)
+			if approve != tc.expectedApprove || found != tc.expectedFound {
+				t.Errorf("expected (%t, %t), got (%t, %t)", tc.expectedApprove, tc.expectedFound, approve, found)
+			}
+		})
+	}
+}
+
+func TestParseApprovalRecord(t *testing.T) {
+	commentID, sha, ok := parseApprovalRecord(ApprovalRecord(42, "abc123") + "\nRecorded the approval of @owner1 on abc123.")
+	if !ok || commentID != 42 || sha != "abc123" {
+		t.Errorf("expected record (42, abc123), got (%d, %q, %t)", commentID, sha, ok)
+	}
+	if _, _, ok := parseApprovalRecord("<!-- codeowners-plus:approval comment=42 -->"); ok {
+		t.Error("expected a record without a sha to be rejected")
+	}
+}
+
+func TestCommentApprovals(t *testing.T) {
+	comment := func(id int64, user, body string) *github.IssueComment {
+		return &github.IssueComment{ID: github.Ptr(id), Body: github.Ptr(body), User: &github.User{Login: github.Ptr(user)}}
+	}
+	userReviewerMap := ghUserReviewerMap{
+		"owner1": codeowners.NewSlugs([]string{"@org/team1"}),
+		"owner2": codeowners.NewSlugs([]string{"@owner2"}),
+		"owner3": codeowners.NewSlugs([]string{"@owner3"}),
+		"owner4": codeowners.NewSlugs([]string{"@owner4"}),
+		"author": codeowners.NewSlugs([]string{"@author"}),
+	}
+	comments := []*github.IssueComment{
+		comment(1, "Owner1", "/approve"),
+		comment(2, "owner2", "/approve"),
+		comment(3, "owner2", "/approve cancel"),
+		comment(4, "outsider", "/approve"),
+		comment(5, "author", "/approve"),
+		comment(6, "owner3", "/approve"),
+		comment(7, "owner1", "LGTM"),
+		comment(8, "owner4", "/approve"),
+	}
+	// The approval of owner4 was never recorded
+	heads := map[int64]string{1: "sha1", 2: "sha1", 4: "sha1", 5: "sha1", 6: "sha2"}
+
+	approvals := commentApprovals(comments, heads, "author", userReviewerMap)
+	expected := []*CurrentApproval{
+		{GHLogin: codeowners.NewSlug("Owner1"), Reviewers: userReviewerMap["owner1"], CommitID: "sha1", CommentID: 1},
+		{GHLogin: codeowners.NewSlug("owner3"), Reviewers: userReviewerMap["owner3"], CommitID: "sha2", CommentID: 6},
+	}
+	if len(approvals) != len(expected) {
+		t.Fatalf("expected %d approvals, got %d: %v", len(expected), len(approvals), approvals)
+	}
+	for i, approval := range approvals {
+		if approval.String() != expected[i].String() {
+			t.Errorf("expected approval %s, got %s", expected[i], approval)
+		}
+	}
+}
+
+func TestGetCommentApprovals(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	approvedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	bot := &github.User{Login: github.Ptr("codeowners-plus[bot]")}
+	gh.pr = &github.PullRequest{Number: github.Ptr(123), User: &github.User{Login: github.Ptr("author")}}
+	gh.userReviewerMap = ghUserReviewerMap{"owner1": codeowners.NewSlugs([]string{"@owner1"}), "owner2": codeowners.NewSlugs([]string{"@owner2"})}
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(bot)
+	})
+	mux.HandleFunc("/repos/test-owner/test-repo/issues/123/comments", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]*github.IssueComment{
+			{ID: github.Ptr[int64](1), Body: github.Ptr("/approve"), User: &github.User{Login: github.Ptr("owner1")}, CreatedAt: &github.Timestamp{Time: approvedAt}},
+			{ID: github.Ptr[int64](2), Body: github.Ptr(ApprovalRecord(1, "sha1")), User: bot},
+			{ID: github.Ptr[int64](3), Body: github.Ptr("/approve"), User: &github.User{Login: github.Ptr("owner2")}},
+			// Records are only trusted from the bot
+			{ID: github.Ptr[int64](4), Body: github.Ptr(ApprovalRecord(3, "sha2")), User: &github.User{Login: github.Ptr("owner2")}},
+		})
+	})
+	// sha2 was pushed after the approval, backdated to before it.  Commit dates are never consulted,
+	// so the approval stays on the head recorded when the comment was handled.
+	mux.HandleFunc("/repos/test-owner/test-repo/pulls/123/commits", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]*github.RepositoryCommit{
+			{SHA: github.Ptr("sha1"), Commit: &github.Commit{Committer: &github.CommitAuthor{Date: &github.Timestamp{Time: approvedAt.Add(-time.Hour)}}}},
+			{SHA: github.Ptr("sha2"), Commit: &github.Commit{Committer: &github.CommitAuthor{Date: &github.Timestamp{Time: approvedAt.Add(-time.Minute)}}}},
+		})
+	})
+
+	approvals, err := gh.GetCommentApprovals()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(approvals) != 1 || approvals[0].CommitID != "sha1" || approvals[0].CommentID != 1 || approvals[0].ReviewID != 0 {
+		t.Errorf("expected a single comment approval on sha1, got %v", approvals)
+	}
+}
@@ -2,12 +2,15 @@ package gh
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"maps"
+	"net/http"
 	"slices"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/go-github/v89/github"
 	"github.com/multimediallc/codeowners-plus/internal/git"
@@ -27,18 +30,32 @@ func (e UserReviewerMapNotInitError) Error() string {
 	return "User reviewer map not initialized"
 }
 
+// TeamMemberSource resolves the member logins of an org team without calling the GitHub API.
+// The boolean result is false when the source has no entry for the team.
+type TeamMemberSource interface {
+	TeamMembers(org, team string) ([]string, bool)
+}
+
 type Client interface {
 	SetWarningBuffer(writer io.Writer)
 	SetInfoBuffer(writer io.Writer)
+	SetTeamRoster(roster TeamMemberSource)
+	SetChildTeamDepth(depth int)
+	SetStatusSHA(sha string)
 	InitPR(pr_id int) error
+	ListOpenPullRequests(base string, labels []string) ([]*github.PullRequest, error)
 	PR() *github.PullRequest
 	InitUserReviewerMap(reviewers []string) error
 	UserReviewers(user string) []codeowners.Slug
+	TeamMembers(team string) []string
+	OpenReviewRequestCounts() (map[string]int, error)
+	BusyUsers(logins []string) ([]string, error)
 	GetTokenUser() (string, error)
 	InitReviews() error
 	AllApprovals() ([]*CurrentApproval, error)
 	FindUserApproval(ghUser string) (*CurrentApproval, error)
 	GetCurrentReviewerApprovals() ([]*CurrentApproval, error)
+	GetCommentApprovals() ([]*CurrentApproval, error)
 	GetAlreadyReviewed() ([]codeowners.Slug, error)
 	GetCurrentlyRequested() ([]codeowners.Slug, error)
 	DismissStaleReviews(staleApprovals []*CurrentApproval) error
@@ -46,14 +63,25 @@ type Client interface {
 	ApprovePR() error
 	InitComments() error
 	AddComment(comment string) error
-	FindExistingComment(prefix string, since *time.Time) (int64, bool, error)
 	UpdateComment(commentID int64, body string) error
-	IsInComments(comment string, since *time.Time) (bool, error)
+	FindComments(prefixes []string) ([]*github.IssueComment, error)
+	FindOwnComments(prefixes []string) ([]*github.IssueComment, error)
+	MinimizeComments(comments []*github.IssueComment) error
+	DeleteComment(commentID int64) error
+	InlineComments(prefix string) ([]*InlineComment, error)
+	CreateInlineReview(comments []*InlineComment) error
+	UpdateInlineComment(commentID int64, body string) error
+	Labels() ([]string, error)
+	EnsureLabel(name, color, description string) error
+	AddLabels(labels []string) error
+	RemoveLabel(label string) error
 	IsSubstringInComments(substring string, since *time.Time) (bool, error)
 	CheckApprovals(fileReviewerMap map[string][]string, approvals []*CurrentApproval, originalDiff git.Diff) (approvers []codeowners.Slug, staleApprovals []*CurrentApproval)
 	IsInLabels(labels []string) (bool, error)
 	IsRepositoryAdmin(username string) (bool, error)
-	ContainsValidBypassApproval(allowedUsers []string) (bool, error)
+	FindBypassApprovals(allowedUsers []string, labels []string) ([]*Bypass, error)
+	CreateCheckRun(name string, result CheckRunResult) error
+	SetCommitStatus(context, state, description string) error
 }
 
 type GHClient struct {
@@ -67,37 +95,166 @@ type GHClient struct {
 	reviews         []*github.PullRequestReview
 	warningBuffer   io.Writer
 	infoBuffer      io.Writer
+	teamRoster      TeamMemberSource
+	childTeamDepth  int
+	statusSHA       string
+	tokenUser       string
+	retryTransport  *retryTransport
+	cacheTransport  *cachingTransport
+}
+
+// clientOptions holds the optional settings applied by NewClient
+type clientOptions struct {
+	cache      Cache
+	cacheTTL   time.Duration
+	maxRetries int
+	appCreds   *AppCredentials
+	apiURL     string
+	uploadURL  string
+}
+
+// ClientOption configures optional behavior of the client created by NewClient
+type ClientOption func(*clientOptions)
+
+// WithCache caches team membership and repository permission responses in cache.
+// Entries younger than ttl are used without an API call, and older entries are
+// revalidated with ETag conditional requests.
+func WithCache(cache Cache, ttl time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.cache = cache
+		o.cacheTTL = ttl
+	}
+}
+
+// WithMaxRetries sets how many times transient API failures and rate limits are
+// retried for idempotent requests.  Defaults to 3, and 0 disables retries.
+func WithMaxRetries(maxRetries int) ClientOption {
+	return func(o *clientOptions) {
+		o.maxRetries = maxRetries
+	}
+}
+
+// WithAppAuth authenticates as a GitHub App installation instead of with a token.
+// Installation tokens are exchanged and refreshed automatically.
+func WithAppAuth(creds AppCredentials) ClientOption {
+	return func(o *clientOptions) {
+		o.appCreds = &creds
+	}
+}
+
+// WithEnterpriseURLs targets a GitHub Enterprise Server instance instead of github.com.
+// uploadURL defaults to the API host when empty.  URLs for github.com itself are ignored.
+func WithEnterpriseURLs(apiURL, uploadURL string) ClientOption {
+	return func(o *clientOptions) {
+		o.apiURL = apiURL
+		o.uploadURL = uploadURL
+	}
+}
+
+// isGitHubDotCom reports whether apiURL is empty or points at the public github.com API
+func isGitHubDotCom(apiURL string) bool {
+	return apiURL == "" || strings.TrimSuffix(apiURL, "/") == "https://api.github.com"
 }
 
-func NewClient(owner, repo, token string) (Client, error) {
-	client, err := github.NewClient(github.WithAuthToken(token))
+func NewClient(owner, repo, token string, opts ...ClientOption) (Client, error) {
+	options := clientOptions{maxRetries: defaultMaxRetries}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// Transports are layered so cached responses skip the network (and retries) entirely
+	retry := newRetryTransport(http.DefaultTransport, options.maxRetries)
+	var transport http.RoundTripper = retry
+	var cacheTransport *cachingTransport
+	if options.cache != nil {
+		cacheTransport = newCachingTransport(transport, options.cache, options.cacheTTL)
+		transport = cacheTransport
+	}
+
+	ghOptions := options.enterpriseOptions()
+	if options.appCreds != nil {
+		appTransport, err := newAppAuthTransport(transport, owner, repo, *options.appCreds, ghOptions...)
+		if err != nil {
+			return nil, err
+		}
+		ghOptions = append(ghOptions, github.WithTransport(appTransport))
+	} else {
+		ghOptions = append(ghOptions, github.WithAuthToken(token), github.WithTransport(transport))
+	}
+	client, err := github.NewClient(ghOptions...)
 	if err != nil {
 		return nil, err
 	}
 	return &GHClient{
-		context.Background(),
-		owner,
-		repo,
-		client,
-		nil,
-		nil,
-		nil,
-		nil,
-		io.Discard,
-		io.Discard,
+		ctx:            context.Background(),
+		owner:          owner,
+		repo:           repo,
+		client:         client,
+		warningBuffer:  io.Discard,
+		infoBuffer:     io.Discard,
+		retryTransport: retry,
+		cacheTransport: cacheTransport,
 	}, nil
 }
 
+// enterpriseOptions points the go-github client at a GitHub Enterprise Server instance when configured
+func (o clientOptions) enterpriseOptions() []github.ClientOptionsFunc {
+	if isGitHubDotCom(o.apiURL) {
+		return nil
+	}
+	uploadURL := o.uploadURL
+	if uploadURL == "" {
+		// GitHub Enterprise Server serves uploads from /api/uploads on the same host
+		uploadURL = strings.TrimSuffix(strings.TrimSuffix(o.apiURL, "/"), "/api/v3")
+	}
+	return []github.ClientOptionsFunc{github.WithEnterpriseURLs(o.apiURL, uploadURL)}
+}
+
 func (gh *GHClient) PR() *github.PullRequest {
 	return gh.pr
 }
 
 func (gh *GHClient) SetWarningBuffer(writer io.Writer) {
 	gh.warningBuffer = writer
+	if gh.retryTransport != nil {
+		gh.retryTransport.warningWriter = writer
+	}
+	if gh.cacheTransport != nil {
+		gh.cacheTransport.warningWriter = writer
+	}
 }
 
 func (gh *GHClient) SetInfoBuffer(writer io.Writer) {
 	gh.infoBuffer = writer
+	if gh.retryTransport != nil {
+		gh.retryTransport.infoWriter = writer
+	}
+}
+
+// SetTeamRoster sets an offline source of team membership which InitUserReviewerMap
+// consults before falling back to the GitHub Teams API
+func (gh *GHClient) SetTeamRoster(roster TeamMemberSource) {
+	gh.teamRoster = roster
+}
+
+// SetChildTeamDepth sets how many levels of child teams InitUserReviewerMap resolves
+// when fetching team members from the GitHub API.  A depth of 0 only includes direct members.
+func (gh *GHClient) SetChildTeamDepth(depth int) {
+	gh.childTeamDepth = max(depth, 0)
+}
+
+// SetStatusSHA reports check runs and commit statuses on sha instead of the PR head commit,
+// e.g. on the temporary merge commit of a merge queue group
+func (gh *GHClient) SetStatusSHA(sha string) {
+	gh.statusSHA = sha
+}
+
+// reportSHA is the commit check runs and commit statuses are reported on
+func (gh *GHClient) reportSHA() string {
+	if gh.statusSHA != "" {
+		return gh.statusSHA
+	}
+	return gh.pr.GetHead().GetSHA()
 }
 
 func (gh *GHClient) InitPR(pr_id int) error {
@@ -112,8 +269,52 @@ func (gh *GHClient) InitPR(pr_id int) error {
 	return nil
 }
 
+// ListOpenPullRequests lists the open PRs of the repository, optionally only those targeting
+// base and those with any of labels
+func (gh *GHClient) ListOpenPullRequests(base string, labels []string) ([]*github.PullRequest, error) {
+	allPulls := make([]*github.PullRequest, 0)
+	listPulls := func(page int) (*github.Response, error) {
+		listOptions := &github.PullRequestListOptions{
+			State:       "open",
+			Base:        base,
+			ListOptions: github.ListOptions{PerPage: 100, Page: page},
+		}
+		pulls, res, err := gh.client.PullRequests.List(gh.ctx, gh.owner, gh.repo, listOptions)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = res.Body.Close()
+		}()
+		allPulls = append(allPulls, pulls...)
+		return res, err
+	}
+	if err := walkPaginatedApi(listPulls); err != nil {
+		return nil, err
+	}
+	if len(labels) == 0 {
+		return allPulls, nil
+	}
+	return f.Filtered(allPulls, func(pull *github.PullRequest) bool {
+		return slices.ContainsFunc(pull.Labels, func(label *github.Label) bool {
+			return slices.ContainsFunc(labels, func(name string) bool {
+				return strings.EqualFold(name, label.GetName())
+			})
+		})
+	}), nil
+}
+
 func (gh *GHClient) InitUserReviewerMap(reviewers []string) error {
 	teamFetch := func(org, team string) []*github.User {
+		if gh.teamRoster != nil {
+			if members, ok := gh.teamRoster.TeamMembers(org, team); ok {
+				_, _ = fmt.Fprintf(gh.infoBuffer, "Using team roster for %s/%s\n", org, team)
+				return f.Map(members, func(login string) *github.User {
+					return &github.User{Login: github.Ptr(login)}
+				})
+			}
+			_, _ = fmt.Fprintf(gh.warningBuffer, "WARNING: Team %s/%s not found in team roster, falling back to GitHub API\n", org, team)
+		}
 		_, _ = fmt.Fprintf(gh.infoBuffer, "Fetching team members for %s/%s\n", org, team)
 		allUsers := make([]*github.User, 0)
 		getMembers := func(page int) (*github.Response, error) {
@@ -131,7 +332,37 @@ func (gh *GHClient) InitUserReviewerMap(reviewers []string) error {
 		}
 		return allUsers
 	}
-	gh.userReviewerMap = makeGHUserReviwerMap(reviewers, teamFetch)
+	childTeamsUnavailable := false
+	childTeamFetch := func(org, team string) []string {
+		if childTeamsUnavailable {
+			return nil
+		}
+		if gh.teamRoster != nil {
+			// Roster teams already include the members of their nested teams
+			if _, ok := gh.teamRoster.TeamMembers(org, team); ok {
+				return nil
+			}
+		}
+		_, _ = fmt.Fprintf(gh.infoBuffer, "Fetching child teams for %s/%s\n", org, team)
+		childTeams := make([]string, 0)
+		getChildren := func(page int) (*github.Response, error) {
+			listOptions := &github.ListOptions{PerPage: 100, Page: page}
+			teams, res, err := gh.client.Teams.ListChildTeamsByParentSlug(gh.ctx, org, team, listOptions)
+			if isNotFound(err) && gh.isEnterprise() {
+				// Older GitHub Enterprise Server versions do not have the child teams endpoint
+				childTeamsUnavailable = true
+				_, _ = fmt.Fprintf(gh.warningBuffer, "WARNING: Child teams are unavailable on this GitHub Enterprise Server version - only direct team members are used\n")
+			} else if err != nil {
+				_, _ = fmt.Fprintf(gh.warningBuffer, "WARNING: Error fetching child teams for %s/%s: %v\n", org, team, err)
+			}
+			childTeams = append(childTeams, f.Map(teams, func(team *github.Team) string { return team.GetSlug() })...)
+			return res, err
+		}
+		_ = walkPaginatedApi(getChildren)
+		return childTeams
+	}
+	resolver := newTeamResolver(gh.childTeamDepth, teamFetch, childTeamFetch, gh.infoBuffer)
+	gh.userReviewerMap = makeGHUserReviwerMap(reviewers, resolver.Members)
 	return nil
 }
 
@@ -142,12 +373,69 @@ func (gh *GHClient) UserReviewers(user string) []codeowners.Slug {
 	return gh.userReviewerMap[strings.ToLower(strings.TrimPrefix(user, "@"))]
 }
 
+// TeamMembers returns the lowercased logins of the members of an owning team, based on the map
+// built by InitUserReviewerMap.  Returns nil if the map has not been initialized.
+func (gh *GHClient) TeamMembers(team string) []string {
+	if gh.userReviewerMap == nil {
+		return nil
+	}
+	return teamMembers(gh.userReviewerMap, codeowners.NewSlug(team))
+}
+
+func teamMembers(userReviewerMap ghUserReviewerMap, team codeowners.Slug) []string {
+	members := make([]string, 0)
+	for login, reviewers := range userReviewerMap {
+		if !strings.Contains(login, "/") && codeowners.ContainsSlug(reviewers, team) {
+			members = append(members, login)
+		}
+	}
+	slices.Sort(members)
+	return members
+}
+
+// OpenReviewRequestCounts counts the pending review requests of every user (by lowercased login)
+// across the open PRs of the repository
+func (gh *GHClient) OpenReviewRequestCounts() (map[string]int, error) {
+	pulls, err := gh.ListOpenPullRequests("", nil)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, pull := range pulls {
+		for _, user := range pull.RequestedReviewers {
+			counts[strings.ToLower(user.GetLogin())]++
+		}
+	}
+	return counts, nil
+}
+
+// GetTokenUser returns the login of the user the token acts as.  Installation tokens (GitHub Apps
+// and the Actions GITHUB_TOKEN) cannot read /user, so the login of their bot user is read from the
+// GraphQL viewer instead.
 func (gh *GHClient) GetTokenUser() (string, error) {
+	if gh.tokenUser != "" {
+		return gh.tokenUser, nil
+	}
 	user, _, err := gh.client.Users.Get(gh.ctx, "")
-	if err != nil {
+	if err == nil {
+		gh.tokenUser = user.GetLogin()
+		return gh.tokenUser, nil
+	}
+	var viewer struct {
+		Viewer struct {
+			Login string `json:"login"`
+		} `json:"viewer"`
+	}
+	if viewerErr := gh.graphQL("query { viewer { login } }", nil, &viewer); viewerErr != nil || viewer.Viewer.Login == "" {
 		return "", err
 	}
-	return user.GetLogin(), nil
+	// GraphQL omits the suffix of bot logins which REST (and comment authors) include
+	login := viewer.Viewer.Login
+	if !strings.HasSuffix(login, "[bot]") {
+		login += "[bot]"
+	}
+	gh.tokenUser = login
+	return gh.tokenUser, nil
 }
 
 func (gh *GHClient) InitReviews() error {
@@ -195,49 +483,62 @@ func (gh *GHClient) approvals() []*github.PullRequestReview {
 	return approvals
 }
 
-// ContainsValidBypassApproval checks if any approval is a valid admin bypass approval
-func (gh *GHClient) ContainsValidBypassApproval(allowedUsers []string) (bool, error) {
+// FindBypassApprovals returns the bypasses of reviews containing the bypass phrase, and of bypass
+// labels on the PR, from users in allowedUsers and repository admins
+func (gh *GHClient) FindBypassApprovals(allowedUsers []string, labels []string) ([]*Bypass, error) {
 	if gh.pr == nil {
-		return false, &NoPRError{}
+		return nil, &NoPRError{}
 	}
 	if gh.reviews == nil {
 		err := gh.InitReviews()
 		if err != nil {
-			return false, err
+			return nil, err
 		}
 	}
 
+	bypasses := make([]*Bypass, 0)
 	for _, review := range gh.reviews {
 		// Check if the review body contains bypass text
-		body := review.GetBody()
-		if !strings.Contains(strings.ToLower(body), "codeowners bypass") {
+		reason, scope, found := ParseBypass(review.GetBody())
+		if !found {
 			continue
 		}
 
 		username := review.GetUser().GetLogin()
-		usernameSlug := codeowners.NewSlug(username)
-
-		// Check if user is in allowed users list
-		for _, allowedUser := range allowedUsers {
-			if usernameSlug.EqualsString(allowedUser) {
-				return true, nil
-			}
+		if gh.isBypassAllowed(username, allowedUsers) {
+			bypasses = append(bypasses, &Bypass{
+				User:      username,
+				Reason:    reason,
+				Scope:     scope,
+				Source:    BypassSourceReview,
+				URL:       review.GetHTMLURL(),
+				CreatedAt: review.GetSubmittedAt().Time,
+			})
 		}
+	}
 
-		// Check if user is repository admin
-		isAdmin, err := gh.IsRepositoryAdmin(username)
-		if err != nil {
-			// Log error but continue checking other approvals
-			_, _ = fmt.Fprintf(gh.warningBuffer, "Warning: Could not check admin status for user %s: %v\n", username, err)
-			continue
-		}
+	labelBypasses, err := gh.bypassLabels(labels, allowedUsers)
+	if err != nil {
+		return nil, err
+	}
+	return append(bypasses, labelBypasses...), nil
+}
 
-		if isAdmin {
-			return true, nil
-		}
+// isBypassAllowed reports whether the user is in allowedUsers or a repository admin
+func (gh *GHClient) isBypassAllowed(username string, allowedUsers []string) bool {
+	// Check if user is in allowed users list
+	if slices.ContainsFunc(allowedUsers, codeowners.NewSlug(username).EqualsString) {
+		return true
 	}
 
-	return false, nil
+	// Check if user is repository admin
+	isAdmin, err := gh.IsRepositoryAdmin(username)
+	if err != nil {
+		// Log error but continue checking other approvals
+		_, _ = fmt.Fprintf(gh.warningBuffer, "Warning: Could not check admin status for user %s: %v\n", username, err)
+		return false
+	}
+	return isAdmin
 }
 
 func (gh *GHClient) AllApprovals() ([]*CurrentApproval, error) {
@@ -251,7 +552,7 @@ func (gh *GHClient) AllApprovals() ([]*CurrentApproval, error) {
 		}
 	}
 	return f.Map(gh.approvals(), func(approval *github.PullRequestReview) *CurrentApproval {
-		return &CurrentApproval{codeowners.NewSlug(approval.User.GetLogin()), approval.GetID(), nil, approval.GetCommitID()}
+		return &CurrentApproval{GHLogin: codeowners.NewSlug(approval.User.GetLogin()), ReviewID: approval.GetID(), CommitID: approval.GetCommitID()}
 	}), nil
 }
 
@@ -273,7 +574,7 @@ func (gh *GHClient) FindUserApproval(ghUser string) (*CurrentApproval, error) {
 	if !found {
 		return nil, nil
 	}
-	return &CurrentApproval{codeowners.NewSlug(review.User.GetLogin()), review.GetID(), nil, review.GetCommitID()}, nil
+	return &CurrentApproval{GHLogin: codeowners.NewSlug(review.User.GetLogin()), ReviewID: review.GetID(), CommitID: review.GetCommitID()}, nil
 }
 
 func (gh *GHClient) GetCurrentReviewerApprovals() ([]*CurrentApproval, error) {
@@ -298,10 +599,10 @@ func currentReviewerApprovalsFromReviews(approvals []*github.PullRequestReview,
 		reviewingUser := review.GetUser().GetLogin()
 		reviewingUserSlug := codeowners.NewSlug(reviewingUser)
 		if reviewers, ok := userReviewerMap[strings.ToLower(reviewingUser)]; ok {
-			newApproval := &CurrentApproval{reviewingUserSlug, review.GetID(), reviewers, review.GetCommitID()}
+			newApproval := &CurrentApproval{GHLogin: reviewingUserSlug, ReviewID: review.GetID(), Reviewers: reviewers, CommitID: review.GetCommitID()}
 			filteredApprovals = append(filteredApprovals, newApproval)
 		} else {
-			newApproval := &CurrentApproval{reviewingUserSlug, review.GetID(), []codeowners.Slug{}, review.GetCommitID()}
+			newApproval := &CurrentApproval{GHLogin: reviewingUserSlug, ReviewID: review.GetID(), Reviewers: []codeowners.Slug{}, CommitID: review.GetCommitID()}
 			filteredApprovals = append(filteredApprovals, newApproval)
 		}
 	}
@@ -477,33 +778,43 @@ func (gh *GHClient) AddComment(comment string) error {
 	return err
 }
 
-func (gh *GHClient) FindExistingComment(prefix string, since *time.Time) (int64, bool, error) {
+// FindComments returns the PR comments starting with any of the (non-empty) prefixes, ignoring leading
+// whitespace, oldest first
+func (gh *GHClient) FindComments(prefixes []string) ([]*github.IssueComment, error) {
 	if gh.pr == nil {
-		return 0, false, &NoPRError{}
+		return nil, &NoPRError{}
 	}
 	if err := gh.InitComments(); err != nil {
-		return 0, false, err
+		return nil, err
 	}
+	return f.Filtered(gh.comments, func(comment *github.IssueComment) bool {
+		return slices.ContainsFunc(prefixes, func(prefix string) bool {
+			return prefix != "" && strings.HasPrefix(strings.TrimLeftFunc(comment.GetBody(), unicode.IsSpace), prefix)
+		})
+	}), nil
+}
 
-	for _, comment := range gh.comments {
-		if since != nil && comment.GetCreatedAt().Before(*since) {
-			continue
-		}
-		if strings.HasPrefix(comment.GetBody(), prefix) {
-			return comment.GetID(), true, nil
-		}
+// FindOwnComments returns the PR comments written by the token's user starting with any of the
+// prefixes, oldest first, so that comments copying the bot's wording are never mistaken for its own
+func (gh *GHClient) FindOwnComments(prefixes []string) ([]*github.IssueComment, error) {
+	comments, err := gh.FindComments(prefixes)
+	if err != nil {
+		return nil, err
+	}
+	tokenUser, err := gh.GetTokenUser()
+	if err != nil {
+		return nil, fmt.Errorf("error identifying the token user: %w", err)
 	}
-	return 0, false, nil
+	return f.Filtered(comments, func(comment *github.IssueComment) bool {
+		return strings.EqualFold(comment.GetUser().GetLogin(), tokenUser)
+	}), nil
 }
 
-func (gh *GHClient) UpdateComment(commentID int64, body string) error {
+func (gh *GHClient) DeleteComment(commentID int64) error {
 	if gh.pr == nil {
 		return &NoPRError{}
 	}
-	comment := &github.IssueComment{
-		Body: &body,
-	}
-	_, res, err := gh.client.Issues.EditComment(gh.ctx, gh.owner, gh.repo, commentID, comment)
+	res, err := gh.client.Issues.DeleteComment(gh.ctx, gh.owner, gh.repo, commentID)
 	if err != nil {
 		return err
 	}
@@ -513,24 +824,21 @@ func (gh *GHClient) UpdateComment(commentID int64, body string) error {
 	return nil
 }
 
-func (gh *GHClient) IsInComments(comment string, since *time.Time) (bool, error) {
+func (gh *GHClient) UpdateComment(commentID int64, body string) error {
 	if gh.pr == nil {
-		return false, &NoPRError{}
+		return &NoPRError{}
 	}
-	if gh.comments == nil {
-		if err := gh.InitComments(); err != nil {
-			_, _ = fmt.Fprintf(gh.warningBuffer, "WARNING: Error initializing comments: %v\n", err)
-		}
+	comment := &github.IssueComment{
+		Body: &body,
 	}
-	for _, c := range gh.comments {
-		if since != nil && c.GetCreatedAt().Before(*since) {
-			continue
-		}
-		if c.GetBody() == comment {
-			return true, nil
-		}
+	_, res, err := gh.client.Issues.EditComment(gh.ctx, gh.owner, gh.repo, commentID, comment)
+	if err != nil {
+		return err
 	}
-	return false, nil
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	return nil
 }
 
 func (gh *GHClient) IsSubstringInComments(substring string, since *time.Time) (bool, error) {
@@ -596,6 +904,17 @@ func (gh *GHClient) IsRepositoryAdmin(username string) (bool, error) {
 	return permission.GetPermission() == "admin", nil
 }
 
+// isEnterprise reports whether the client targets a GitHub Enterprise Server instance
+func (gh *GHClient) isEnterprise() bool {
+	return !isGitHubDotCom(gh.client.BaseURL())
+}
+
+// isNotFound reports whether err is a 404 response from the GitHub API
+func isNotFound(err error) bool {
+	var errorResponse *github.ErrorResponse
+	return errors.As(err, &errorResponse) && errorResponse.Response != nil && errorResponse.Response.StatusCode == http.StatusNotFound
+}
+
 type ghUserReviewerMap map[string][]codeowners.Slug
 
 type CurrentApproval struct {
@@ -603,6 +922,8 @@ type CurrentApproval struct {
 	ReviewID  int64
 	Reviewers []codeowners.Slug
 	CommitID  string
+	// CommentID is set for approvals from an `/approve` comment, which have no review to dismiss
+	CommentID int64
 }
 
 func (p *CurrentApproval) String() string {
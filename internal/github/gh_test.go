@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -239,44 +241,6 @@ func TestMakeGHUserReviewerMap(t *testing.T) {
 	}
 }
 
-func TestIsInComments(t *testing.T) {
-	gh := &GHClient{
-		pr: &github.PullRequest{Number: github.Ptr(1)},
-		comments: []*github.IssueComment{
-			{Body: github.Ptr("comment1"), CreatedAt: &github.Timestamp{Time: time.Now().AddDate(0, 0, -2)}},
-			{Body: github.Ptr("comment2"), CreatedAt: &github.Timestamp{Time: time.Now().AddDate(0, 0, -1)}},
-			{Body: github.Ptr("comment3"), CreatedAt: &github.Timestamp{Time: time.Now()}},
-		},
-	}
-
-	tt := []struct {
-		name   string
-		string string
-		since  *time.Time
-		found  bool
-	}{
-		{name: "find comment1 with no time filter", string: "comment1", since: nil, found: true},
-		{name: "find comment2 with no time filter", string: "comment2", since: nil, found: true},
-		{name: "find comment3 with no time filter", string: "comment3", since: nil, found: true},
-		{name: "non-existent comment", string: "comment4", since: nil, found: false},
-		{name: "comment1 filtered by time", string: "comment1", since: &gh.comments[1].CreatedAt.Time, found: false},
-		{name: "comment2 filtered by time", string: "comment2", since: &gh.comments[1].CreatedAt.Time, found: true},
-		{name: "comment3 filtered by time", string: "comment3", since: &gh.comments[1].CreatedAt.Time, found: true},
-	}
-
-	for _, tc := range tt {
-		t.Run(tc.name, func(t *testing.T) {
-			found, err := gh.IsInComments(tc.string, tc.since)
-			if err != nil {
-				t.Errorf("Unexpected error: %v", err)
-			}
-			if found != tc.found {
-				t.Errorf("Expected found to be %t, got %t", tc.found, found)
-			}
-		})
-	}
-}
-
 func TestIsSubstringInComments(t *testing.T) {
 	gh := &GHClient{
 		pr: &github.PullRequest{Number: github.Ptr(1)},
@@ -367,6 +331,13 @@ func TestNilPRErr(t *testing.T) {
 				return err
 			},
 		},
+		{
+			name: "GetCommentApprovals",
+			testFn: func() error {
+				_, err := gh.GetCommentApprovals()
+				return err
+			},
+		},
 		{
 			name: "ApprovePR",
 			testFn: func() error {
@@ -418,19 +389,25 @@ func TestNilPRErr(t *testing.T) {
 			},
 		},
 		{
-			name: "IsInComments",
+			name: "IsSubstringInComments",
 			testFn: func() error {
-				_, err := gh.IsInComments("comment", nil)
+				_, err := gh.IsSubstringInComments("comment", nil)
 				return err
 			},
 		},
 		{
-			name: "IsSubstringInComments",
+			name: "FindComments",
 			testFn: func() error {
-				_, err := gh.IsSubstringInComments("comment", nil)
+				_, err := gh.FindComments([]string{"prefix"})
 				return err
 			},
 		},
+		{
+			name: "DeleteComment",
+			testFn: func() error {
+				return gh.DeleteComment(1)
+			},
+		},
 		{
 			name: "IsInLabels",
 			testFn: func() error {
@@ -474,6 +451,13 @@ func TestNilUserReviewerMapErr(t *testing.T) {
 				return err
 			},
 		},
+		{
+			name: "GetCommentApprovals",
+			testFn: func() error {
+				_, err := gh.GetCommentApprovals()
+				return err
+			},
+		},
 	}
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
@@ -554,12 +538,6 @@ func TestNilCommentsErr(t *testing.T) {
 		name   string
 		testFn func() (bool, error)
 	}{
-		{
-			name: "IsInComments",
-			testFn: func() (bool, error) {
-				return gh.IsInComments("", nil)
-			},
-		},
 		{
 			name: "IsSubstringInComments",
 			testFn: func() (bool, error) {
@@ -1032,6 +1010,85 @@ func TestInitUserReviewerMap(t *testing.T) {
 	}
 }
 
+type mockTeamRoster map[string][]string
+
+func (m mockTeamRoster) TeamMembers(org, team string) ([]string, bool) {
+	members, ok := m[org+"/"+team]
+	return members, ok
+}
+
+func TestInitUserReviewerMapWithRoster(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	gh.pr = &github.PullRequest{Number: github.Ptr(123)}
+	gh.SetTeamRoster(mockTeamRoster{
+		"org1/team1": {"roster_member1", "roster_member2"},
+	})
+
+	// org1/team1 must be resolved from the roster without calling the API
+	mux.HandleFunc("/orgs/org1/teams/team1/members", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected team roster to be used instead of the API")
+	})
+	// org2/team2 is not in the roster and falls back to the API
+	mux.HandleFunc("/orgs/org2/teams/team2/members", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]*github.User{
+			{Login: github.Ptr("roster_member1")},
+		})
+	})
+
+	err := gh.InitUserReviewerMap([]string{"@org1/team1", "@org2/team2"})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	expectedMap := ghUserReviewerMap{
+		"roster_member1": codeowners.NewSlugs([]string{"@org1/team1", "@org2/team2"}),
+		"roster_member2": codeowners.NewSlugs([]string{"@org1/team1"}),
+	}
+	for user := range expectedMap {
+		if !f.SlicesItemsMatch(gh.userReviewerMap[user], expectedMap[user]) {
+			t.Errorf("expected user %s to map to %v, got %v", user, expectedMap[user], gh.userReviewerMap[user])
+		}
+	}
+}
+
+func TestInitUserReviewerMapWithRosterChildTeams(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	gh.pr = &github.PullRequest{Number: github.Ptr(123)}
+	gh.SetChildTeamDepth(2)
+	gh.SetTeamRoster(mockTeamRoster{
+		"org1/team1": {"roster_member1"},
+	})
+
+	// Roster teams include their nested teams, so child teams are not fetched from the API
+	mux.HandleFunc("/orgs/org1/teams/team1/teams", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expected no child team lookup for a roster team")
+	})
+	childTeamLookups := 0
+	mux.HandleFunc("/orgs/org2/teams/team2/members", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]*github.User{{Login: github.Ptr("api_member")}})
+	})
+	mux.HandleFunc("/orgs/org2/teams/team2/teams", func(w http.ResponseWriter, r *http.Request) {
+		childTeamLookups++
+		_ = json.NewEncoder(w).Encode([]*github.Team{})
+	})
+
+	err := gh.InitUserReviewerMap([]string{"@org1/team1", "@org2/team2"})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if childTeamLookups != 1 {
+		t.Errorf("expected child teams of the API team to be fetched once, got %d", childTeamLookups)
+	}
+	if !f.SlicesItemsMatch(gh.userReviewerMap["roster_member1"], codeowners.NewSlugs([]string{"@org1/team1"})) {
+		t.Errorf("expected roster_member1 to map to @org1/team1, got %v", gh.userReviewerMap["roster_member1"])
+	}
+}
+
 func TestIsInLabels(t *testing.T) {
 	tt := []struct {
 		name        string
@@ -1123,111 +1180,105 @@ func TestIsInLabels(t *testing.T) {
 	}
 }
 
-func TestFindExistingComment(t *testing.T) {
-	tt := []struct {
-		name          string
-		comments      []*github.IssueComment
-		prefix        string
-		since         *time.Time
-		expectedID    int64
-		expectedFound bool
-		expectedError bool
-	}{
-		{
-			name: "comment found",
-			comments: []*github.IssueComment{
-				{
-					ID:   github.Ptr[int64](1),
-					Body: github.Ptr("Codeowners approval required for this PR:\n- [ ] @user1"),
-				},
-			},
-			prefix:        "Codeowners approval required for this PR:",
-			expectedID:    1,
-			expectedFound: true,
-			expectedError: false,
-		},
-		{
-			name: "comment not found",
-			comments: []*github.IssueComment{
-				{
-					ID:   github.Ptr[int64](1),
-					Body: github.Ptr("Some other comment"),
-				},
-			},
-			prefix:        "Codeowners approval required for this PR:",
-			expectedID:    0,
-			expectedFound: false,
-			expectedError: false,
-		},
-		{
-			name: "comment too old",
-			comments: []*github.IssueComment{
-				{
-					ID:        github.Ptr[int64](1),
-					Body:      github.Ptr("Codeowners approval required for this PR:\n- [ ] @user1"),
-					CreatedAt: &github.Timestamp{Time: time.Now().AddDate(0, 0, -6)}, // 6 days old
-				},
-			},
-			prefix:        "Codeowners approval required for this PR:",
-			since:         func() *time.Time { t := time.Now().AddDate(0, 0, -5); return &t }(), // 5 days ago
-			expectedID:    0,
-			expectedFound: false,
-			expectedError: false,
-		},
-		{
-			name: "comment within time range",
-			comments: []*github.IssueComment{
-				{
-					ID:        github.Ptr[int64](1),
-					Body:      github.Ptr("Codeowners approval required for this PR:\n- [ ] @user1"),
-					CreatedAt: &github.Timestamp{Time: time.Now().AddDate(0, 0, -4)}, // 4 days old
-				},
-			},
-			prefix:        "Codeowners approval required for this PR:",
-			since:         func() *time.Time { t := time.Now().AddDate(0, 0, -5); return &t }(), // 5 days ago
-			expectedID:    1,
-			expectedFound: true,
-			expectedError: false,
-		},
+func TestFindComments(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	gh.pr = &github.PullRequest{Number: github.Ptr(123)}
+	mux.HandleFunc("/repos/test-owner/test-repo/issues/123/comments", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]*github.IssueComment{
+			{ID: github.Ptr[int64](1), Body: github.Ptr("<!-- codeowners-plus:cc v=1 -->\ncc @user1")},
+			{ID: github.Ptr[int64](2), Body: github.Ptr("Some other comment")},
+			{ID: github.Ptr[int64](3), Body: github.Ptr("cc @user2")},
+			{ID: github.Ptr[int64](4), Body: github.Ptr("<!-- codeowners-plus:cc v=1 -->\ncc @user3")},
+			{ID: github.Ptr[int64](5), Body: github.Ptr("\n Codeowners approval required")},
+		})
+	})
+
+	comments, err := gh.FindComments([]string{"<!-- codeowners-plus:cc ", "Codeowners approval required"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ids := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.GetID())
 	}
+	if !slices.Equal(ids, []int64{1, 4, 5}) {
+		t.Errorf("expected comments [1 4 5], got %v", ids)
+	}
+}
 
-	for _, tc := range tt {
-		t.Run(tc.name, func(t *testing.T) {
-			mux, server, gh := mockServerAndClient(t)
-			defer server.Close()
+func TestFindOwnComments(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
 
-			gh.pr = &github.PullRequest{Number: github.Ptr(123)}
+	gh.pr = &github.PullRequest{Number: github.Ptr(123)}
+	bot := &github.User{Login: github.Ptr("codeowners-plus[bot]")}
+	human := &github.User{Login: github.Ptr("developer")}
+	mux.HandleFunc("/repos/test-owner/test-repo/issues/123/comments", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]*github.IssueComment{
+			{ID: github.Ptr[int64](1), Body: github.Ptr("<!-- codeowners-plus:cc v=1 -->\ncc @user1"), User: bot},
+			{ID: github.Ptr[int64](2), Body: github.Ptr("<!-- codeowners-plus:cc v=1 -->\ncc @user2"), User: human},
+			{ID: github.Ptr[int64](3), Body: github.Ptr("Some other comment"), User: bot},
+		})
+	})
+	// Installation tokens cannot read /user, and the bot login comes from the GraphQL viewer
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Resource not accessible by integration", http.StatusForbidden)
+	})
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"viewer": {"login": "codeowners-plus"}}}`))
+	})
 
-			// Mock the GitHub API endpoint
-			mux.HandleFunc("/repos/test-owner/test-repo/issues/123/comments", func(w http.ResponseWriter, r *http.Request) {
-				if r.Method != http.MethodGet {
-					t.Errorf("expected method GET, got %s", r.Method)
-				}
-				w.Header().Set("Content-Type", "application/json")
-				_ = json.NewEncoder(w).Encode(tc.comments)
-			})
+	comments, err := gh.FindOwnComments([]string{"<!-- codeowners-plus:cc ", ""})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ids := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		ids = append(ids, comment.GetID())
+	}
+	if !slices.Equal(ids, []int64{1}) {
+		t.Errorf("expected comments [1], got %v", ids)
+	}
+}
 
-			id, found, err := gh.FindExistingComment(tc.prefix, tc.since)
-			if tc.expectedError {
-				if err == nil {
-					t.Error("expected an error, got nil")
-				}
-				return
-			}
+func TestFindOwnCommentsUnknownTokenUser(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
 
-			if err != nil {
-				t.Errorf("unexpected error: %v", err)
-				return
-			}
+	gh.pr = &github.PullRequest{Number: github.Ptr(123)}
+	mux.HandleFunc("/repos/test-owner/test-repo/issues/123/comments", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]*github.IssueComment{{ID: github.Ptr[int64](1), Body: github.Ptr("<!-- codeowners-plus:cc v=1 -->\ncc @user1")}})
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	})
 
-			if found != tc.expectedFound {
-				t.Errorf("expected found to be %t, got %t", tc.expectedFound, found)
-			}
+	if _, err := gh.FindOwnComments([]string{"<!-- codeowners-plus:cc "}); err == nil {
+		t.Error("expected an error when the token user cannot be identified, got nil")
+	}
+}
 
-			if id != tc.expectedID {
-				t.Errorf("expected ID to be %d, got %d", tc.expectedID, id)
-			}
-		})
+func TestDeleteComment(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	gh.pr = &github.PullRequest{Number: github.Ptr(123)}
+	deleted := false
+	mux.HandleFunc("/repos/test-owner/test-repo/issues/comments/5", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("expected method DELETE, got %s", r.Method)
+		}
+		deleted = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if err := gh.DeleteComment(5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !deleted {
+		t.Error("expected the comment to be deleted")
 	}
 }
 
@@ -1294,7 +1345,7 @@ func TestUpdateComment(t *testing.T) {
 	}
 }
 
-func TestContainsValidBypassApproval(t *testing.T) {
+func TestFindBypassApprovals(t *testing.T) {
 	tt := []struct {
 		name         string
 		reviews      []*github.PullRequestReview
@@ -1442,7 +1493,7 @@ func TestContainsValidBypassApproval(t *testing.T) {
 				}
 			}
 
-			result, err := gh.ContainsValidBypassApproval(tc.allowedUsers)
+			result, err := gh.FindBypassApprovals(tc.allowedUsers, nil)
 
 			if tc.expectError {
 				if err == nil {
@@ -1456,28 +1507,28 @@ func TestContainsValidBypassApproval(t *testing.T) {
 				return
 			}
 
-			if result != tc.expected {
+			if (len(result) > 0) != tc.expected {
 				t.Errorf("expected %v, got %v", tc.expected, result)
 			}
 		})
 	}
 }
 
-func TestContainsValidBypassApprovalNoPR(t *testing.T) {
+func TestFindBypassApprovalsNoPR(t *testing.T) {
 	c, err := NewClient("test-owner", "test-repo", "test-token")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	gh := c.(*GHClient)
 
-	result, err := gh.ContainsValidBypassApproval([]string{})
+	result, err := gh.FindBypassApprovals([]string{}, nil)
 
 	if err == nil {
 		t.Error("expected NoPRError, got nil")
 	}
 
-	if result {
-		t.Error("expected false result when no PR is set")
+	if result != nil {
+		t.Error("expected no bypasses when no PR is set")
 	}
 
 	var noPRErr *NoPRError
@@ -1581,3 +1632,292 @@ func TestIsRepositoryAdminError(t *testing.T) {
 		t.Error("expected false result on error")
 	}
 }
+
+func TestTeamResolverMembers(t *testing.T) {
+	members := map[string][]string{
+		"parent":     {"user1", "user2"},
+		"child":      {"user2", "user3"},
+		"grandchild": {"user4"},
+	}
+	children := map[string][]string{
+		"parent":     {"child"},
+		"child":      {"grandchild"},
+		"grandchild": {"parent"}, // cycle back to the root
+	}
+
+	tt := []struct {
+		name     string
+		depth    int
+		team     string
+		expected []string
+	}{
+		{name: "direct members only", depth: 0, team: "parent", expected: []string{"user1", "user2"}},
+		{name: "one level of child teams", depth: 1, team: "parent", expected: []string{"user1", "user2", "user3"}},
+		{name: "all levels with cycle", depth: 5, team: "parent", expected: []string{"user1", "user2", "user3", "user4"}},
+		{name: "child team as root", depth: 5, team: "child", expected: []string{"user1", "user2", "user3", "user4"}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			memberCalls := make(map[string]int)
+			fetchMembers := func(org, team string) []*github.User {
+				memberCalls[team]++
+				return f.Map(members[team], func(login string) *github.User {
+					return &github.User{Login: github.Ptr(login)}
+				})
+			}
+			fetchChildren := func(org, team string) []string {
+				return children[team]
+			}
+			resolver := newTeamResolver(tc.depth, fetchMembers, fetchChildren, io.Discard)
+
+			users := resolver.Members("org", tc.team)
+			logins := f.Map(users, func(user *github.User) string { return user.GetLogin() })
+			if !f.SlicesItemsMatch(logins, tc.expected) {
+				t.Errorf("expected members %v, got %v", tc.expected, logins)
+			}
+
+			// Resolving again must be served from the cache
+			_ = resolver.Members("org", tc.team)
+			for team, calls := range memberCalls {
+				if calls != 1 {
+					t.Errorf("expected members of %s to be fetched once, got %d", team, calls)
+				}
+			}
+		})
+	}
+}
+
+func TestInitUserReviewerMapChildTeams(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	infoBuffer := &strings.Builder{}
+	gh.SetInfoBuffer(infoBuffer)
+	gh.SetChildTeamDepth(2)
+
+	mux.HandleFunc("/orgs/org/teams/parent/members", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]*github.User{{Login: github.Ptr("parent_member")}})
+	})
+	mux.HandleFunc("/orgs/org/teams/parent/teams", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]*github.Team{{Slug: github.Ptr("child")}})
+	})
+	mux.HandleFunc("/orgs/org/teams/child/members", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]*github.User{{Login: github.Ptr("child_member")}})
+	})
+	mux.HandleFunc("/orgs/org/teams/child/teams", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]*github.Team{})
+	})
+
+	err := gh.InitUserReviewerMap([]string{"@org/parent"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, user := range []string{"parent_member", "child_member"} {
+		if !f.SlicesItemsMatch(gh.userReviewerMap[user], codeowners.NewSlugs([]string{"@org/parent"})) {
+			t.Errorf("expected %s to satisfy @org/parent, got %v", user, gh.userReviewerMap[user])
+		}
+	}
+	expectedHierarchy := "Resolved team hierarchy for org/parent:\n- org/parent\n  - org/child\n"
+	if !strings.Contains(infoBuffer.String(), expectedHierarchy) {
+		t.Errorf("expected verbose output to contain %q, got %q", expectedHierarchy, infoBuffer.String())
+	}
+}
+
+func TestNewGithubClientEnterpriseURLs(t *testing.T) {
+	tt := []struct {
+		name            string
+		apiURL          string
+		uploadURL       string
+		expectedBaseURL string
+		expectedUpload  string
+	}{
+		{
+			name:            "github.com by default",
+			apiURL:          "",
+			expectedBaseURL: "https://api.github.com/",
+			expectedUpload:  "https://uploads.github.com/",
+		},
+		{
+			name:            "github.com api url is ignored",
+			apiURL:          "https://api.github.com",
+			expectedBaseURL: "https://api.github.com/",
+			expectedUpload:  "https://uploads.github.com/",
+		},
+		{
+			name:            "enterprise server",
+			apiURL:          "https://ghes.example.com/api/v3",
+			expectedBaseURL: "https://ghes.example.com/api/v3/",
+			expectedUpload:  "https://ghes.example.com/api/uploads/",
+		},
+		{
+			name:            "enterprise server with upload url",
+			apiURL:          "https://ghes.example.com",
+			uploadURL:       "https://uploads.ghes.example.com",
+			expectedBaseURL: "https://ghes.example.com/api/v3/",
+			expectedUpload:  "https://uploads.ghes.example.com/",
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewClient("owner", "repo", "token", WithEnterpriseURLs(tc.apiURL, tc.uploadURL))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			client := c.(*GHClient)
+			if client.client.BaseURL() != tc.expectedBaseURL {
+				t.Errorf("expected base URL %s, got %s", tc.expectedBaseURL, client.client.BaseURL())
+			}
+			if client.client.UploadURL() != tc.expectedUpload {
+				t.Errorf("expected upload URL %s, got %s", tc.expectedUpload, client.client.UploadURL())
+			}
+		})
+	}
+}
+
+func TestInitUserReviewerMapChildTeamsUnavailable(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	warningBuffer := &strings.Builder{}
+	gh.SetWarningBuffer(warningBuffer)
+	gh.SetChildTeamDepth(2)
+
+	childTeamCalls := 0
+	for _, team := range []string{"team1", "team2"} {
+		mux.HandleFunc("/orgs/org/teams/"+team+"/members", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode([]*github.User{{Login: github.Ptr(team + "_member")}})
+		})
+		// Simulate an older GitHub Enterprise Server without the child teams endpoint
+		mux.HandleFunc("/orgs/org/teams/"+team+"/teams", func(w http.ResponseWriter, r *http.Request) {
+			childTeamCalls++
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+		})
+	}
+
+	if err := gh.InitUserReviewerMap([]string{"@org/team1", "@org/team2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if childTeamCalls != 1 {
+		t.Errorf("expected child teams endpoint to be called once, got %d", childTeamCalls)
+	}
+	if strings.Count(warningBuffer.String(), "Child teams are unavailable") != 1 {
+		t.Errorf("expected a single unavailable warning, got %q", warningBuffer.String())
+	}
+	for _, team := range []string{"team1", "team2"} {
+		expected := codeowners.NewSlugs([]string{"@org/" + team})
+		if !f.SlicesItemsMatch(gh.userReviewerMap[team+"_member"], expected) {
+			t.Errorf("expected direct members of %s to still be mapped, got %v", team, gh.userReviewerMap[team+"_member"])
+		}
+	}
+}
+
+func TestListOpenPullRequests(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	mux.HandleFunc("/repos/test-owner/test-repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != "open" {
+			t.Errorf("expected open PRs, got state %q", r.URL.Query().Get("state"))
+		}
+		pulls := []*github.PullRequest{
+			{Number: github.Ptr(1), Labels: []*github.Label{{Name: github.Ptr("Sweep")}}},
+			{Number: github.Ptr(2)},
+			{Number: github.Ptr(3), Labels: []*github.Label{{Name: github.Ptr("other")}, {Name: github.Ptr("needs-review")}}},
+		}
+		_ = json.NewEncoder(w).Encode(pulls)
+	})
+
+	tt := []struct {
+		name        string
+		labels      []string
+		expectedPRs []int
+	}{
+		{
+			name:        "no label filter",
+			labels:      nil,
+			expectedPRs: []int{1, 2, 3},
+		},
+		{
+			name:        "any of the labels, case insensitive",
+			labels:      []string{"sweep", "needs-review"},
+			expectedPRs: []int{1, 3},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			pulls, err := gh.ListOpenPullRequests("", tc.labels)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			prs := f.Map(pulls, func(pull *github.PullRequest) int { return pull.GetNumber() })
+			if !slices.Equal(prs, tc.expectedPRs) {
+				t.Errorf("expected PRs %v, got %v", tc.expectedPRs, prs)
+			}
+		})
+	}
+}
+
+func TestTeamMembers(t *testing.T) {
+	userReviewerMap := ghUserReviewerMap{
+		"carol":     codeowners.NewSlugs([]string{"@carol", "@org/team1"}),
+		"alice":     codeowners.NewSlugs([]string{"@alice", "@org/team1", "@org/team2"}),
+		"bob":       codeowners.NewSlugs([]string{"@bob", "@org/team2"}),
+		"org/team1": codeowners.NewSlugs([]string{"@org/team1"}),
+	}
+	gh := &GHClient{userReviewerMap: userReviewerMap}
+
+	tt := []struct {
+		name     string
+		team     string
+		expected []string
+	}{
+		{name: "members sorted by login", team: "@org/team1", expected: []string{"alice", "carol"}},
+		{name: "case insensitive team", team: "@Org/Team2", expected: []string{"alice", "bob"}},
+		{name: "unknown team", team: "@org/team3", expected: []string{}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			got := gh.TeamMembers(tc.team)
+			if !slices.Equal(got, tc.expected) {
+				t.Errorf("expected members %v, got %v", tc.expected, got)
+			}
+		})
+	}
+
+	if members := (&GHClient{}).TeamMembers("@org/team1"); members != nil {
+		t.Errorf("expected nil members before InitUserReviewerMap, got %v", members)
+	}
+}
+
+func TestOpenReviewRequestCounts(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	mux.HandleFunc("/repos/test-owner/test-repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		pulls := []*github.PullRequest{
+			{Number: github.Ptr(1), RequestedReviewers: []*github.User{{Login: github.Ptr("Alice")}, {Login: github.Ptr("bob")}}},
+			{Number: github.Ptr(2), RequestedReviewers: []*github.User{{Login: github.Ptr("alice")}}},
+			{Number: github.Ptr(3)},
+		}
+		_ = json.NewEncoder(w).Encode(pulls)
+	})
+
+	counts, err := gh.OpenReviewRequestCounts()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]int{"alice": 2, "bob": 1}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("expected counts %v, got %v", expected, counts)
+	}
+}
@@ -0,0 +1,132 @@
+package gh
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v89/github"
+)
+
+// graphQLBatchSize limits the number of aliased fields per GraphQL query
+const graphQLBatchSize = 50
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// graphQL runs a GraphQL query, decoding its data into result.  Errors reported alongside data
+// (e.g. for a user which does not exist) are written to the warning buffer.
+func (gh *GHClient) graphQL(query string, variables map[string]any, result any) error {
+	url := "graphql"
+	if gh.isEnterprise() {
+		// GitHub Enterprise Server serves GraphQL from /api/graphql rather than under /api/v3
+		url = strings.TrimSuffix(gh.client.BaseURL(), "v3/") + "graphql"
+	}
+	req, err := gh.client.NewRequest(gh.ctx, http.MethodPost, url, graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+	response := struct {
+		Data   any            `json:"data"`
+		Errors []graphQLError `json:"errors"`
+	}{Data: result}
+	res, err := gh.client.Do(req, &response)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	if response.Data == nil && len(response.Errors) > 0 {
+		return fmt.Errorf("graphql error: %s", response.Errors[0].Message)
+	}
+	for _, graphQLErr := range response.Errors {
+		_, _ = fmt.Fprintf(gh.warningBuffer, "WARNING: GraphQL: %s\n", graphQLErr.Message)
+	}
+	return nil
+}
+
+// BusyUsers returns the users whose GitHub status is set to busy (limited availability)
+func (gh *GHClient) BusyUsers(logins []string) ([]string, error) {
+	busy := make([]string, 0)
+	for start := 0; start < len(logins); start += graphQLBatchSize {
+		batch := logins[start:min(start+graphQLBatchSize, len(logins))]
+		params := make([]string, 0, len(batch))
+		fields := make([]string, 0, len(batch))
+		variables := make(map[string]any, len(batch))
+		for i, login := range batch {
+			params = append(params, fmt.Sprintf("$u%d: String!", i))
+			fields = append(fields, fmt.Sprintf("u%d: user(login: $u%d) { status { indicatesLimitedAvailability } }", i, i))
+			variables[fmt.Sprintf("u%d", i)] = strings.TrimPrefix(login, "@")
+		}
+		query := fmt.Sprintf("query(%s) { %s }", strings.Join(params, ", "), strings.Join(fields, " "))
+
+		type userStatus struct {
+			Status *struct {
+				IndicatesLimitedAvailability bool `json:"indicatesLimitedAvailability"`
+			} `json:"status"`
+		}
+		users := make(map[string]*userStatus)
+		if err := gh.graphQL(query, variables, &users); err != nil {
+			return nil, err
+		}
+		for i, login := range batch {
+			user := users[fmt.Sprintf("u%d", i)]
+			if user != nil && user.Status != nil && user.Status.IndicatesLimitedAvailability {
+				busy = append(busy, login)
+			}
+		}
+	}
+	return busy, nil
+}
+
+// MinimizeComments hides the comments as outdated, skipping comments which are already minimized
+func (gh *GHClient) MinimizeComments(comments []*github.IssueComment) error {
+	ids := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		if comment.GetNodeID() != "" {
+			ids = append(ids, comment.GetNodeID())
+		}
+	}
+	for start := 0; start < len(ids); start += graphQLBatchSize {
+		batch := ids[start:min(start+graphQLBatchSize, len(ids))]
+
+		var nodes struct {
+			Nodes []*struct {
+				ID          string `json:"id"`
+				IsMinimized bool   `json:"isMinimized"`
+			} `json:"nodes"`
+		}
+		query := "query($ids: [ID!]!) { nodes(ids: $ids) { ... on IssueComment { id isMinimized } } }"
+		if err := gh.graphQL(query, map[string]any{"ids": batch}, &nodes); err != nil {
+			return err
+		}
+
+		params := make([]string, 0, len(batch))
+		fields := make([]string, 0, len(batch))
+		variables := make(map[string]any, len(batch))
+		for i, node := range nodes.Nodes {
+			if node == nil || node.ID == "" || node.IsMinimized {
+				continue
+			}
+			params = append(params, fmt.Sprintf("$c%d: ID!", i))
+			fields = append(fields, fmt.Sprintf("c%d: minimizeComment(input: {subjectId: $c%d, classifier: OUTDATED}) { clientMutationId }", i, i))
+			variables[fmt.Sprintf("c%d", i)] = node.ID
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		mutation := fmt.Sprintf("mutation(%s) { %s }", strings.Join(params, ", "), strings.Join(fields, " "))
+		var result map[string]any
+		if err := gh.graphQL(mutation, variables, &result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
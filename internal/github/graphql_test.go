@@ -0,0 +1,100 @@
+package gh
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v89/github"
+)
+
+func TestBusyUsers(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	requests := 0
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var request graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("invalid request: %v", err)
+		}
+		data := make(map[string]any)
+		for alias, login := range request.Variables {
+			switch login {
+			case "busy-user":
+				data[alias] = map[string]any{"status": map[string]any{"indicatesLimitedAvailability": true}}
+			case "missing-user":
+				data[alias] = nil
+			default:
+				data[alias] = map[string]any{"status": nil}
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data":   data,
+			"errors": []map[string]any{{"message": "Could not resolve to a User with the login of 'missing-user'."}},
+		})
+	})
+
+	logins := []string{"@busy-user", "free-user", "missing-user"}
+	for i := range graphQLBatchSize {
+		logins = append(logins, strings.Repeat("x", i+1))
+	}
+	busy, err := gh.BusyUsers(logins)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(busy, []string{"@busy-user"}) {
+		t.Errorf("expected busy users [@busy-user], got %v", busy)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 batched requests, got %d", requests)
+	}
+}
+
+func TestBusyUsersError(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": null, "errors": [{"message": "Bad credentials"}]}`))
+	})
+
+	if _, err := gh.BusyUsers([]string{"alice"}); err == nil {
+		t.Error("expected error but got none")
+	}
+}
+
+func TestMinimizeComments(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	minimized := make([]string, 0)
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		var request graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("invalid request: %v", err)
+		}
+		if strings.HasPrefix(request.Query, "query") {
+			_, _ = w.Write([]byte(`{"data": {"nodes": [{"id": "IC_1", "isMinimized": false}, {"id": "IC_2", "isMinimized": true}]}}`))
+			return
+		}
+		for _, id := range request.Variables {
+			minimized = append(minimized, id.(string))
+		}
+		_, _ = w.Write([]byte(`{"data": {"c0": {"clientMutationId": null}}}`))
+	})
+
+	err := gh.MinimizeComments([]*github.IssueComment{
+		{ID: github.Ptr[int64](1), NodeID: github.Ptr("IC_1")},
+		{ID: github.Ptr[int64](2), NodeID: github.Ptr("IC_2")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(minimized, []string{"IC_1"}) {
+		t.Errorf("expected only IC_1 to be minimized, got %v", minimized)
+	}
+}
@@ -0,0 +1,79 @@
+package gh
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v89/github"
+)
+
+// Labels returns the names of the PR labels
+func (gh *GHClient) Labels() ([]string, error) {
+	if gh.pr == nil {
+		return nil, &NoPRError{}
+	}
+	labels := make([]string, 0, len(gh.pr.Labels))
+	for _, label := range gh.pr.Labels {
+		labels = append(labels, label.GetName())
+	}
+	return labels, nil
+}
+
+// EnsureLabel creates the label in the repository if it does not exist yet
+func (gh *GHClient) EnsureLabel(name, color, description string) error {
+	_, res, err := gh.client.Issues.GetLabel(gh.ctx, gh.owner, gh.repo, name)
+	if err == nil {
+		_ = res.Body.Close()
+		return nil
+	}
+	if !isNotFound(err) {
+		return err
+	}
+	label := &github.Label{
+		Name:  github.Ptr(name),
+		Color: github.Ptr(strings.TrimPrefix(color, "#")),
+	}
+	if description != "" {
+		label.Description = github.Ptr(description)
+	}
+	_, res, err = gh.client.Issues.CreateLabel(gh.ctx, gh.owner, gh.repo, label)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	return nil
+}
+
+// AddLabels adds the labels to the PR
+func (gh *GHClient) AddLabels(labels []string) error {
+	if gh.pr == nil {
+		return &NoPRError{}
+	}
+	added, res, err := gh.client.Issues.AddLabelsToIssue(gh.ctx, gh.owner, gh.repo, gh.pr.GetNumber(), labels)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	gh.pr.Labels = added
+	return nil
+}
+
+// RemoveLabel removes the label from the PR
+func (gh *GHClient) RemoveLabel(label string) error {
+	if gh.pr == nil {
+		return &NoPRError{}
+	}
+	res, err := gh.client.Issues.RemoveLabelForIssue(gh.ctx, gh.owner, gh.repo, gh.pr.GetNumber(), label)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	gh.pr.Labels = slices.DeleteFunc(gh.pr.Labels, func(l *github.Label) bool { return l.GetName() == label })
+	return nil
+}
@@ -0,0 +1,106 @@
+package gh
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v89/github"
+)
+
+func TestLabels(t *testing.T) {
+	gh := &GHClient{pr: &github.PullRequest{Labels: []*github.Label{{Name: github.Ptr("bug")}, {Name: github.Ptr("owner:backend")}}}}
+	labels, err := gh.Labels()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(labels, []string{"bug", "owner:backend"}) {
+		t.Errorf("expected labels [bug owner:backend], got %v", labels)
+	}
+}
+
+func TestEnsureLabel(t *testing.T) {
+	tt := []struct {
+		name            string
+		exists          bool
+		expectedCreated bool
+	}{
+		{name: "existing label", exists: true, expectedCreated: false},
+		{name: "missing label", exists: false, expectedCreated: true},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			mux, server, gh := mockServerAndClient(t)
+			defer server.Close()
+
+			mux.HandleFunc("/repos/test-owner/test-repo/labels/owner:backend", func(w http.ResponseWriter, r *http.Request) {
+				if !tc.exists {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(&github.Label{Name: github.Ptr("owner:backend")})
+			})
+			created := false
+			mux.HandleFunc("/repos/test-owner/test-repo/labels", func(w http.ResponseWriter, r *http.Request) {
+				var label github.Label
+				if err := json.NewDecoder(r.Body).Decode(&label); err != nil {
+					t.Fatalf("failed to decode request body: %v", err)
+				}
+				if label.GetName() != "owner:backend" || label.GetColor() != "ededed" {
+					t.Errorf("unexpected label %v", label)
+				}
+				created = true
+				w.WriteHeader(http.StatusCreated)
+				_ = json.NewEncoder(w).Encode(&label)
+			})
+
+			if err := gh.EnsureLabel("owner:backend", "#ededed", ""); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if created != tc.expectedCreated {
+				t.Errorf("expected created %t, got %t", tc.expectedCreated, created)
+			}
+		})
+	}
+}
+
+func TestAddAndRemoveLabels(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	gh.pr = &github.PullRequest{Number: github.Ptr(123), Labels: []*github.Label{{Name: github.Ptr("owner:old")}}}
+	mux.HandleFunc("/repos/test-owner/test-repo/issues/123/labels", func(w http.ResponseWriter, r *http.Request) {
+		var labels []string
+		if err := json.NewDecoder(r.Body).Decode(&labels); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		if !slices.Equal(labels, []string{"owner:new"}) {
+			t.Errorf("expected labels [owner:new], got %v", labels)
+		}
+		_ = json.NewEncoder(w).Encode([]*github.Label{{Name: github.Ptr("owner:old")}, {Name: github.Ptr("owner:new")}})
+	})
+	removed := false
+	mux.HandleFunc("/repos/test-owner/test-repo/issues/123/labels/owner:old", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("expected method DELETE, got %s", r.Method)
+		}
+		removed = true
+		_ = json.NewEncoder(w).Encode([]*github.Label{{Name: github.Ptr("owner:new")}})
+	})
+
+	if err := gh.AddLabels([]string{"owner:new"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := gh.RemoveLabel("owner:old"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !removed {
+		t.Error("expected the label to be removed")
+	}
+	labels, _ := gh.Labels()
+	if !slices.Equal(labels, []string{"owner:new"}) {
+		t.Errorf("expected labels [owner:new], got %v", labels)
+	}
+}
@@ -0,0 +1,95 @@
+package gh
+
+import (
+	"strings"
+
+	"github.com/google/go-github/v89/github"
+)
+
+// InlineComment is a review comment on a line of a changed file
+type InlineComment struct {
+	ID   int64
+	Path string
+	Line int
+	Body string
+}
+
+// InlineComments returns the PR review comments starting with prefix, oldest first
+func (gh *GHClient) InlineComments(prefix string) ([]*InlineComment, error) {
+	if gh.pr == nil {
+		return nil, &NoPRError{}
+	}
+	inlineComments := make([]*InlineComment, 0)
+	listComments := func(page int) (*github.Response, error) {
+		listOptions := &github.PullRequestListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100, Page: page}}
+		comments, res, err := gh.client.PullRequests.ListComments(gh.ctx, gh.owner, gh.repo, gh.pr.GetNumber(), listOptions)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = res.Body.Close()
+		}()
+		for _, comment := range comments {
+			if strings.HasPrefix(comment.GetBody(), prefix) {
+				inlineComments = append(inlineComments, &InlineComment{
+					ID:   comment.GetID(),
+					Path: comment.GetPath(),
+					Line: comment.GetLine(),
+					Body: comment.GetBody(),
+				})
+			}
+		}
+		return res, err
+	}
+	if err := walkPaginatedApi(listComments); err != nil {
+		return nil, err
+	}
+	return inlineComments, nil
+}
+
+// CreateInlineReview posts a review (without approving or requesting changes) made of the inline
+// comments, on the PR head commit
+func (gh *GHClient) CreateInlineReview(comments []*InlineComment) error {
+	if gh.pr == nil {
+		return &NoPRError{}
+	}
+	draftComments := make([]*github.DraftReviewComment, 0, len(comments))
+	for _, comment := range comments {
+		draftComments = append(draftComments, &github.DraftReviewComment{
+			Path: github.Ptr(comment.Path),
+			Line: github.Ptr(comment.Line),
+			Side: github.Ptr("RIGHT"),
+			Body: github.Ptr(comment.Body),
+		})
+	}
+	createReviewOptions := &github.PullRequestReviewRequest{
+		CommitID: github.Ptr(gh.pr.GetHead().GetSHA()),
+		Event:    github.Ptr("COMMENT"),
+		Comments: draftComments,
+	}
+	_, res, err := gh.client.PullRequests.CreateReview(gh.ctx, gh.owner, gh.repo, gh.pr.GetNumber(), createReviewOptions)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	return nil
+}
+
+func (gh *GHClient) UpdateInlineComment(commentID int64, body string) error {
+	if gh.pr == nil {
+		return &NoPRError{}
+	}
+	comment := &github.PullRequestComment{
+		Body: &body,
+	}
+	_, res, err := gh.client.PullRequests.EditComment(gh.ctx, gh.owner, gh.repo, commentID, comment)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	return nil
+}
@@ -0,0 +1,87 @@
+package gh
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v89/github"
+)
+
+func TestInlineComments(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	gh.pr = &github.PullRequest{Number: github.Ptr(123)}
+	mux.HandleFunc("/repos/test-owner/test-repo/pulls/123/comments", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode([]*github.PullRequestComment{
+			{ID: github.Ptr[int64](1), Path: github.Ptr("a.go"), Line: github.Ptr(3), Body: github.Ptr("<!-- marker -->\nowners")},
+			{ID: github.Ptr[int64](2), Path: github.Ptr("a.go"), Line: github.Ptr(4), Body: github.Ptr("nit: typo")},
+		})
+	})
+
+	comments, err := gh.InlineComments("<!-- marker -->")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Fatalf("expected 1 comment, got %d", len(comments))
+	}
+	expected := InlineComment{ID: 1, Path: "a.go", Line: 3, Body: "<!-- marker -->\nowners"}
+	if *comments[0] != expected {
+		t.Errorf("expected comment %+v, got %+v", expected, *comments[0])
+	}
+}
+
+func TestCreateInlineReview(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	gh.pr = &github.PullRequest{Number: github.Ptr(123), Head: &github.PullRequestBranch{SHA: github.Ptr("abc123")}}
+	called := false
+	mux.HandleFunc("/repos/test-owner/test-repo/pulls/123/reviews", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		var req github.PullRequestReviewRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		if req.GetEvent() != "COMMENT" || req.GetCommitID() != "abc123" {
+			t.Errorf("expected a COMMENT review on abc123, got %s on %s", req.GetEvent(), req.GetCommitID())
+		}
+		if len(req.Comments) != 1 || req.Comments[0].GetPath() != "a.go" || req.Comments[0].GetLine() != 3 || req.Comments[0].GetSide() != "RIGHT" {
+			t.Errorf("unexpected review comments: %v", req.Comments)
+		}
+		_ = json.NewEncoder(w).Encode(&github.PullRequestReview{ID: github.Ptr[int64](1)})
+	})
+
+	if err := gh.CreateInlineReview([]*InlineComment{{Path: "a.go", Line: 3, Body: "owners"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected the review to be created")
+	}
+}
+
+func TestUpdateInlineComment(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+
+	gh.pr = &github.PullRequest{Number: github.Ptr(123)}
+	mux.HandleFunc("/repos/test-owner/test-repo/pulls/comments/7", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Errorf("expected method PATCH, got %s", r.Method)
+		}
+		var req github.PullRequestComment
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		if req.GetBody() != "updated" {
+			t.Errorf("expected body 'updated', got %q", req.GetBody())
+		}
+		_ = json.NewEncoder(w).Encode(&github.PullRequestComment{ID: github.Ptr[int64](7)})
+	})
+
+	if err := gh.UpdateInlineComment(7, "updated"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
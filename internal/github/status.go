@@ -0,0 +1,40 @@
+package gh
+
+import (
+	"github.com/google/go-github/v89/github"
+)
+
+// GitHub rejects commit status descriptions longer than 140 characters
+const maxCommitStatusDescription = 140
+
+const (
+	CommitStatePending = "pending"
+	CommitStateSuccess = "success"
+	CommitStateFailure = "failure"
+	// CommitStateError reports that the evaluation itself failed
+	CommitStateError = "error"
+)
+
+// SetCommitStatus sets the commit status for context on the PR head commit (or the SetStatusSHA commit).
+// Setting a status again with the same context replaces the previous one, so the status can be
+// updated from any workflow (e.g. on pull_request_review) without rerunning the original job.
+func (gh *GHClient) SetCommitStatus(context, state, description string) error {
+	if gh.pr == nil {
+		return &NoPRError{}
+	}
+	if runes := []rune(description); len(runes) > maxCommitStatusDescription {
+		description = string(runes[:maxCommitStatusDescription-1]) + "…"
+	}
+	_, res, err := gh.client.Repositories.CreateStatus(gh.ctx, gh.owner, gh.repo, gh.reportSHA(), github.RepoStatus{
+		State:       github.Ptr(state),
+		Description: github.Ptr(description),
+		Context:     github.Ptr(context),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+	return nil
+}
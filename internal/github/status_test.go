@@ -0,0 +1,46 @@
+package gh
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v89/github"
+)
+
+func TestSetCommitStatus(t *testing.T) {
+	mux, server, gh := mockServerAndClient(t)
+	defer server.Close()
+	gh.pr = &github.PullRequest{Number: github.Ptr(1), Head: &github.PullRequestBranch{SHA: github.Ptr("abc123")}}
+
+	var status github.RepoStatus
+	mux.HandleFunc("/repos/test-owner/test-repo/statuses/abc123", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&status); err != nil {
+			t.Fatalf("error decoding request: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(&status)
+	})
+
+	description := strings.Repeat("x", 200)
+	if err := gh.SetCommitStatus("codeowners-plus", CommitStateFailure, description); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.GetContext() != "codeowners-plus" || status.GetState() != CommitStateFailure {
+		t.Errorf("expected codeowners-plus failure, got %s %s", status.GetContext(), status.GetState())
+	}
+	if got := []rune(status.GetDescription()); len(got) != maxCommitStatusDescription {
+		t.Errorf("expected description truncated to %d characters, got %d", maxCommitStatusDescription, len(got))
+	}
+}
+
+func TestSetCommitStatusNoPR(t *testing.T) {
+	gh := &GHClient{}
+	err := gh.SetCommitStatus("codeowners-plus", CommitStatePending, "")
+	if _, ok := err.(*NoPRError); !ok {
+		t.Errorf("expected NoPRError, got %v", err)
+	}
+}
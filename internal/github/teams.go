@@ -0,0 +1,91 @@
+package gh
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/go-github/v89/github"
+)
+
+// teamResolver resolves the members of a team, including members of its child
+// teams up to maxDepth levels deep.  API results are memoized per team so teams
+// shared between several owners are only fetched once.
+type teamResolver struct {
+	maxDepth      int
+	fetchMembers  func(org, team string) []*github.User
+	fetchChildren func(org, team string) []string
+	infoWriter    io.Writer
+	memberCache   map[string][]*github.User
+	childCache    map[string][]string
+}
+
+func newTeamResolver(
+	maxDepth int,
+	fetchMembers func(org, team string) []*github.User,
+	fetchChildren func(org, team string) []string,
+	infoWriter io.Writer,
+) *teamResolver {
+	return &teamResolver{
+		maxDepth:      maxDepth,
+		fetchMembers:  fetchMembers,
+		fetchChildren: fetchChildren,
+		infoWriter:    infoWriter,
+		memberCache:   make(map[string][]*github.User),
+		childCache:    make(map[string][]string),
+	}
+}
+
+// Members returns the deduplicated members of org/team and its child teams
+func (r *teamResolver) Members(org, team string) []*github.User {
+	users := make([]*github.User, 0)
+	seenUsers := make(map[string]bool)
+	visited := make(map[string]bool)
+	hierarchy := strings.Builder{}
+
+	var walk func(team string, depth int)
+	walk = func(team string, depth int) {
+		key := strings.ToLower(fmt.Sprintf("%s/%s", org, team))
+		indent := strings.Repeat("  ", depth)
+		// Guard against cycles in the team hierarchy
+		if visited[key] {
+			// builder.WriteString error return is always nil
+			_, _ = fmt.Fprintf(&hierarchy, "%s- %s/%s (already included)\n", indent, org, team)
+			return
+		}
+		visited[key] = true
+		_, _ = fmt.Fprintf(&hierarchy, "%s- %s/%s\n", indent, org, team)
+
+		members, ok := r.memberCache[key]
+		if !ok {
+			members = r.fetchMembers(org, team)
+			r.memberCache[key] = members
+		}
+		for _, user := range members {
+			login := strings.ToLower(user.GetLogin())
+			if seenUsers[login] {
+				continue
+			}
+			seenUsers[login] = true
+			users = append(users, user)
+		}
+
+		if depth >= r.maxDepth {
+			return
+		}
+		children, ok := r.childCache[key]
+		if !ok {
+			children = r.fetchChildren(org, team)
+			r.childCache[key] = children
+		}
+		for _, child := range children {
+			walk(child, depth+1)
+		}
+	}
+	walk(team, 0)
+
+	if r.maxDepth > 0 {
+		_, _ = fmt.Fprintf(r.infoWriter, "Resolved team hierarchy for %s/%s:\n%s", org, team, hierarchy.String())
+	}
+	return users
+}
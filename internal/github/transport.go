@@ -0,0 +1,174 @@
+package gh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = time.Second
+	// maxRetryWait caps how long a single retry may wait, so an exhausted
+	// primary rate limit fails fast instead of stalling the check for an hour
+	maxRetryWait = time.Minute
+)
+
+// retryTransport retries idempotent GitHub API requests which fail with transient
+// errors (5xx responses, network errors) or rate limits, using exponential backoff
+// and honoring the Retry-After and X-RateLimit-Reset headers
+type retryTransport struct {
+	base          http.RoundTripper
+	maxRetries    int
+	backoff       time.Duration
+	now           func() time.Time
+	sleep         func(ctx context.Context, d time.Duration) error
+	infoWriter    io.Writer
+	warningWriter io.Writer
+}
+
+func newRetryTransport(base http.RoundTripper, maxRetries int) *retryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryTransport{
+		base:          base,
+		maxRetries:    max(maxRetries, 0),
+		backoff:       defaultRetryBackoff,
+		now:           time.Now,
+		sleep:         sleepContext,
+		infoWriter:    io.Discard,
+		warningWriter: io.Discard,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq, err := rewindRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+		res, err := t.base.RoundTrip(attemptReq)
+		if res != nil {
+			t.logRateLimit(req, res)
+		}
+
+		if attempt >= t.maxRetries || !isIdempotent(req.Method) {
+			return res, err
+		}
+		wait, retry := t.retryDelay(res, err, attempt)
+		if !retry {
+			return res, err
+		}
+
+		reason := "network error"
+		if err == nil {
+			reason = res.Status
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+		}
+		_, _ = fmt.Fprintf(t.warningWriter, "WARNING: GitHub API %s %s failed (%s), retrying in %s (attempt %d/%d)\n",
+			req.Method, req.URL.Path, reason, wait, attempt+1, t.maxRetries)
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryDelay decides whether a response should be retried and how long to wait first
+func (t *retryTransport) retryDelay(res *http.Response, err error, attempt int) (time.Duration, bool) {
+	backoff := min(t.backoff<<attempt, maxRetryWait)
+	if err != nil {
+		return backoff, true
+	}
+
+	if retryAfter := res.Header.Get("Retry-After"); retryAfter != "" && isRateLimitStatus(res.StatusCode) {
+		seconds, parseErr := strconv.Atoi(retryAfter)
+		if parseErr == nil {
+			wait := time.Duration(seconds) * time.Second
+			return wait, wait <= maxRetryWait
+		}
+	}
+
+	if isRateLimitStatus(res.StatusCode) && res.Header.Get("X-RateLimit-Remaining") == "0" {
+		reset, parseErr := strconv.ParseInt(res.Header.Get("X-RateLimit-Reset"), 10, 64)
+		if parseErr != nil {
+			return 0, false
+		}
+		wait := max(time.Unix(reset, 0).Sub(t.now()), 0)
+		return wait, wait <= maxRetryWait
+	}
+
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests:
+		return backoff, true
+	case res.StatusCode == http.StatusForbidden && isSecondaryRateLimit(res):
+		return backoff, true
+	case res.StatusCode == http.StatusBadGateway,
+		res.StatusCode == http.StatusServiceUnavailable,
+		res.StatusCode == http.StatusGatewayTimeout,
+		res.StatusCode == http.StatusInternalServerError:
+		return backoff, true
+	}
+	return 0, false
+}
+
+func (t *retryTransport) logRateLimit(req *http.Request, res *http.Response) {
+	remaining := res.Header.Get("X-RateLimit-Remaining")
+	if remaining == "" {
+		return
+	}
+	_, _ = fmt.Fprintf(t.infoWriter, "GitHub API %s %s: %d (rate limit remaining %s/%s)\n",
+		req.Method, req.URL.Path, res.StatusCode, remaining, res.Header.Get("X-RateLimit-Limit"))
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func isRateLimitStatus(status int) bool {
+	return status == http.StatusForbidden || status == http.StatusTooManyRequests
+}
+
+// isSecondaryRateLimit checks the error message GitHub returns for secondary rate
+// limits without a Retry-After header, leaving the body readable for the caller
+func isSecondaryRateLimit(res *http.Response) bool {
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	return err == nil && strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+// rewindRequest returns a request with a fresh body for retry attempts
+func rewindRequest(req *http.Request, attempt int) (*http.Request, error) {
+	if attempt == 0 || req.Body == nil || req.GetBody == nil {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	retryReq := req.Clone(req.Context())
+	retryReq.Body = body
+	return retryReq, nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
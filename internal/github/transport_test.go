@@ -0,0 +1,222 @@
+package gh
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tt := []struct {
+		name             string
+		method           string
+		maxRetries       int
+		responses        []func(w http.ResponseWriter)
+		expectedStatus   int
+		expectedRequests int
+		expectedWaits    []time.Duration
+	}{
+		{
+			name:       "success is not retried",
+			method:     http.MethodGet,
+			maxRetries: 3,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 1,
+			expectedWaits:    nil,
+		},
+		{
+			name:       "bad gateway is retried with exponential backoff",
+			method:     http.MethodGet,
+			maxRetries: 3,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusServiceUnavailable) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 3,
+			expectedWaits:    []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:       "retries are capped",
+			method:     http.MethodGet,
+			maxRetries: 2,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			expectedStatus:   http.StatusBadGateway,
+			expectedRequests: 3,
+			expectedWaits:    []time.Duration{time.Second, 2 * time.Second},
+		},
+		{
+			name:       "non-idempotent requests are not retried",
+			method:     http.MethodPost,
+			maxRetries: 3,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) },
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			expectedStatus:   http.StatusBadGateway,
+			expectedRequests: 1,
+			expectedWaits:    nil,
+		},
+		{
+			name:       "retry-after is honored for secondary rate limits",
+			method:     http.MethodGet,
+			maxRetries: 3,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("Retry-After", "7")
+					w.WriteHeader(http.StatusForbidden)
+				},
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 2,
+			expectedWaits:    []time.Duration{7 * time.Second},
+		},
+		{
+			name:       "secondary rate limit message without retry-after",
+			method:     http.MethodGet,
+			maxRetries: 3,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.WriteHeader(http.StatusForbidden)
+					_, _ = w.Write([]byte(`{"message": "You have exceeded a secondary rate limit"}`))
+				},
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 2,
+			expectedWaits:    []time.Duration{time.Second},
+		},
+		{
+			name:       "rate limit reset is honored",
+			method:     http.MethodGet,
+			maxRetries: 3,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(20*time.Second).Unix(), 10))
+					w.WriteHeader(http.StatusForbidden)
+				},
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			expectedStatus:   http.StatusOK,
+			expectedRequests: 2,
+			expectedWaits:    []time.Duration{20 * time.Second},
+		},
+		{
+			name:       "rate limit reset too far in the future fails fast",
+			method:     http.MethodGet,
+			maxRetries: 3,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(time.Hour).Unix(), 10))
+					w.WriteHeader(http.StatusForbidden)
+				},
+				func(w http.ResponseWriter) { w.WriteHeader(http.StatusOK) },
+			},
+			expectedStatus:   http.StatusForbidden,
+			expectedRequests: 1,
+			expectedWaits:    nil,
+		},
+		{
+			name:       "permission errors are not retried",
+			method:     http.MethodGet,
+			maxRetries: 3,
+			responses: []func(w http.ResponseWriter){
+				func(w http.ResponseWriter) {
+					w.WriteHeader(http.StatusForbidden)
+					_, _ = w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+				},
+			},
+			expectedStatus:   http.StatusForbidden,
+			expectedRequests: 1,
+			expectedWaits:    nil,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tc.responses[min(requests, len(tc.responses)-1)](w)
+				requests++
+			}))
+			defer server.Close()
+
+			var waits []time.Duration
+			transport := newRetryTransport(http.DefaultTransport, tc.maxRetries)
+			transport.now = func() time.Time { return now }
+			transport.sleep = func(ctx context.Context, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+
+			req, err := http.NewRequest(tc.method, server.URL+"/repos/owner/repo", strings.NewReader("{}"))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			res, err := transport.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			_, _ = io.Copy(io.Discard, res.Body)
+			_ = res.Body.Close()
+
+			if res.StatusCode != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d", tc.expectedStatus, res.StatusCode)
+			}
+			if requests != tc.expectedRequests {
+				t.Errorf("expected %d requests, got %d", tc.expectedRequests, requests)
+			}
+			if len(waits) != len(tc.expectedWaits) {
+				t.Fatalf("expected waits %v, got %v", tc.expectedWaits, waits)
+			}
+			for i := range waits {
+				if waits[i] != tc.expectedWaits[i] {
+					t.Errorf("expected waits %v, got %v", tc.expectedWaits, waits)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportLogsRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	infoBuffer := &strings.Builder{}
+	transport := newRetryTransport(http.DefaultTransport, 3)
+	transport.infoWriter = infoBuffer
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/user", nil)
+	res, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = res.Body.Close()
+
+	expected := "GitHub API GET /user: 200 (rate limit remaining 4999/5000)\n"
+	if infoBuffer.String() != expected {
+		t.Errorf("expected %q, got %q", expected, infoBuffer.String())
+	}
+}
@@ -0,0 +1,291 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v89/github"
+	"github.com/multimediallc/codeowners-plus/internal/app"
+	"github.com/multimediallc/codeowners-plus/internal/git"
+	gh "github.com/multimediallc/codeowners-plus/internal/github"
+)
+
+// pullRequestActions are the pull_request webhook actions which can change the codeowners result
+var pullRequestActions = []string{"opened", "reopened", "synchronize", "ready_for_review", "labeled", "unlabeled", "edited"}
+
+// reviewActions are the pull_request_review webhook actions which can change the codeowners result
+var reviewActions = []string{"submitted", "dismissed", "edited"}
+
+// Config configures the webhook server
+type Config struct {
+	Addr          string
+	WebhookSecret []byte
+	// CloneDir holds a bare clone of every repository events are received for
+	CloneDir string
+	// App is the configuration each evaluation starts from; Repo, PR and RepoDir are set per event
+	App app.Config
+	// Log receives a summary of every evaluation (and its warnings)
+	Log io.Writer
+}
+
+// job is a single PR evaluation triggered by a webhook
+type job struct {
+	repo           string
+	pr             int
+	baseRef        string
+	cloneURL       string
+	installationID int64
+	// command is the slash command which triggered the evaluation, if any
+	command *app.Command
+}
+
+func (j job) String() string {
+	return fmt.Sprintf("%s#%d", j.repo, j.pr)
+}
+
+// Server receives GitHub webhooks and runs the codeowners evaluation for the affected PRs
+type Server struct {
+	config Config
+
+	mu        sync.Mutex
+	cache     *gh.MemoryCache
+	repoLocks map[string]*sync.Mutex
+	logMu     sync.Mutex
+
+	jobs sync.WaitGroup
+	run  func(j job) error
+}
+
+// New creates a Server.  Team membership and permission lookups are cached in memory across
+// events until a team or membership webhook reports a change.
+func New(config Config) *Server {
+	if config.Log == nil {
+		config.Log = io.Discard
+	}
+	s := &Server{
+		config:    config,
+		cache:     gh.NewMemoryCache(),
+		repoLocks: make(map[string]*sync.Mutex),
+	}
+	s.run = s.evaluate
+	return s
+}
+
+// ListenAndServe serves webhooks until ctx is cancelled, then waits for running evaluations to finish
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.Handle("/webhook", s)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	httpServer := &http.Server{
+		Addr:              s.config.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.ListenAndServe()
+	}()
+	s.logf("Listening for webhooks on %s/webhook\n", s.config.Addr)
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := httpServer.Shutdown(shutdownCtx)
+	s.jobs.Wait()
+	return err
+}
+
+// ServeHTTP verifies and handles a single webhook delivery.  Evaluations run in the background
+// since GitHub expects a response within 10 seconds.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	payload, err := github.ValidatePayload(r, s.config.WebhookSecret)
+	if err != nil {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	event, err := github.ParseWebHook(github.WebHookType(r), payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	switch e := event.(type) {
+	case *github.PullRequestEvent:
+		if !slices.Contains(pullRequestActions, e.GetAction()) {
+			break
+		}
+		s.dispatch(job{
+			repo:           e.GetRepo().GetFullName(),
+			pr:             e.GetNumber(),
+			baseRef:        e.GetPullRequest().GetBase().GetRef(),
+			cloneURL:       e.GetRepo().GetCloneURL(),
+			installationID: e.GetInstallation().GetID(),
+		})
+		w.WriteHeader(http.StatusAccepted)
+		return
+	case *github.PullRequestReviewEvent:
+		if !slices.Contains(reviewActions, e.GetAction()) {
+			break
+		}
+		s.dispatch(job{
+			repo:           e.GetRepo().GetFullName(),
+			pr:             e.GetPullRequest().GetNumber(),
+			baseRef:        e.GetPullRequest().GetBase().GetRef(),
+			cloneURL:       e.GetRepo().GetCloneURL(),
+			installationID: e.GetInstallation().GetID(),
+		})
+		w.WriteHeader(http.StatusAccepted)
+		return
+	case *github.IssueCommentEvent:
+		command := app.NewCommand(e)
+		if command == nil {
+			break
+		}
+		// The base branch of the PR is not part of the event, it is looked up before fetching
+		s.dispatch(job{
+			repo:           e.GetRepo().GetFullName(),
+			pr:             command.PR,
+			cloneURL:       e.GetRepo().GetCloneURL(),
+			installationID: e.GetInstallation().GetID(),
+			command:        command,
+		})
+		w.WriteHeader(http.StatusAccepted)
+		return
+	case *github.TeamEvent:
+		s.resetCache(fmt.Sprintf("team %s %s", e.GetTeam().GetSlug(), e.GetAction()))
+	case *github.MembershipEvent:
+		s.resetCache(fmt.Sprintf("membership of %s %s", e.GetTeam().GetSlug(), e.GetAction()))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) dispatch(j job) {
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+		if err := s.run(j); err != nil {
+			s.logf("[%s] ERROR: %v\n", j, err)
+		}
+	}()
+}
+
+// resetCache drops cached team membership after a team changes, so the next evaluation sees it
+func (s *Server) resetCache(reason string) {
+	s.mu.Lock()
+	s.cache = gh.NewMemoryCache()
+	s.mu.Unlock()
+	s.logf("Cleared team cache (%s)\n", reason)
+}
+
+func (s *Server) currentCache() *gh.MemoryCache {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cache
+}
+
+// repoLock serializes evaluations per repository, since they share a bare clone
+func (s *Server) repoLock(repo string) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lock, ok := s.repoLocks[repo]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.repoLocks[repo] = lock
+	}
+	return lock
+}
+
+// evaluate fetches the PR into the repository's bare clone and runs the App pipeline for it
+func (s *Server) evaluate(j job) error {
+	lock := s.repoLock(j.repo)
+	lock.Lock()
+	defer lock.Unlock()
+
+	cfg := s.config.App
+	cfg.Repo = j.repo
+	cfg.PR = j.pr
+	cfg.Cache = s.currentCache()
+
+	cfg.Command = j.command
+	if cfg.AppID != 0 && cfg.AppInstallationID == 0 {
+		cfg.AppInstallationID = j.installationID
+	}
+
+	if j.baseRef == "" {
+		client, err := app.NewClient(cfg)
+		if err != nil {
+			return fmt.Errorf("failed to initialize client: %w", err)
+		}
+		if err := client.InitPR(j.pr); err != nil {
+			return fmt.Errorf("InitPR Error: %v", err)
+		}
+		j.baseRef = client.PR().GetBase().GetRef()
+	}
+
+	token, err := cfg.GitToken(context.Background())
+	if err != nil {
+		return err
+	}
+	clone := git.NewBareClone(filepath.Join(s.config.CloneDir, j.repo+".git"), j.cloneURL)
+	err = clone.Fetch(token,
+		fmt.Sprintf("+refs/heads/%s:refs/heads/%s", j.baseRef, j.baseRef),
+		fmt.Sprintf("+refs/pull/%d/head:refs/pull/%d/head", j.pr, j.pr),
+	)
+	if err != nil {
+		return err
+	}
+	cfg.RepoDir = clone.Dir()
+
+	infoBuffer := &bytes.Buffer{}
+	warningBuffer := &bytes.Buffer{}
+	cfg.InfoBuffer = infoBuffer
+	cfg.WarningBuffer = warningBuffer
+	defer func() {
+		s.logBuffer(j, warningBuffer)
+		if cfg.Verbose {
+			s.logBuffer(j, infoBuffer)
+		}
+	}()
+
+	a, err := app.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize app: %w", err)
+	}
+	outputData, err := a.Run()
+	if err != nil {
+		return err
+	}
+	s.logf("[%s] %s\n", j, strings.ReplaceAll(outputData.Message, "\n", " "))
+	return nil
+}
+
+func (s *Server) logBuffer(j job, buffer *bytes.Buffer) {
+	for line := range strings.Lines(buffer.String()) {
+		s.logf("[%s] %s\n", j, strings.TrimSuffix(line, "\n"))
+	}
+}
+
+func (s *Server) logf(format string, args ...any) {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+	_, _ = fmt.Fprintf(s.config.Log, format, args...)
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/multimediallc/codeowners-plus/internal/app"
+)
+
+var testSecret = []byte("webhook-secret")
+
+func signedRequest(t *testing.T, event, payloadFile string, secret []byte) *http.Request {
+	t.Helper()
+	payload, err := os.ReadFile("testdata/" + payloadFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	req := httptest.NewRequest(http.MethodPost, "/webhook", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", event)
+	req.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestServeHTTP(t *testing.T) {
+	tt := []struct {
+		name           string
+		event          string
+		payloadFile    string
+		secret         []byte
+		expectedStatus int
+		expectedJob    *job
+	}{
+		{
+			name:           "pull request synchronize runs evaluation",
+			event:          "pull_request",
+			payloadFile:    "pull_request.json",
+			secret:         testSecret,
+			expectedStatus: http.StatusAccepted,
+			expectedJob: &job{
+				repo:           "acme/widgets",
+				pr:             42,
+				baseRef:        "main",
+				cloneURL:       "https://github.com/acme/widgets.git",
+				installationID: 5551,
+			},
+		},
+		{
+			name:           "pull request review runs evaluation",
+			event:          "pull_request_review",
+			payloadFile:    "pull_request_review.json",
+			secret:         testSecret,
+			expectedStatus: http.StatusAccepted,
+			expectedJob: &job{
+				repo:           "acme/widgets",
+				pr:             7,
+				baseRef:        "release",
+				cloneURL:       "https://github.com/acme/widgets.git",
+				installationID: 5551,
+			},
+		},
+		{
+			name:           "slash command runs evaluation",
+			event:          "issue_comment",
+			payloadFile:    "issue_comment.json",
+			secret:         testSecret,
+			expectedStatus: http.StatusAccepted,
+			expectedJob: &job{
+				repo:           "acme/widgets",
+				pr:             9,
+				cloneURL:       "https://github.com/acme/widgets.git",
+				installationID: 5551,
+				command: &app.Command{
+					PR:        9,
+					Name:      app.CommandExplain,
+					Args:      []string{"src/main.go"},
+					Text:      "/codeowners explain src/main.go",
+					Author:    "octocat",
+					CommentID: 90,
+				},
+			},
+		},
+		{
+			name:           "comment without command is ignored",
+			event:          "issue_comment",
+			payloadFile:    "issue_comment_plain.json",
+			secret:         testSecret,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "closed pull request is ignored",
+			event:          "pull_request",
+			payloadFile:    "pull_request_closed.json",
+			secret:         testSecret,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "membership change is acknowledged",
+			event:          "membership",
+			payloadFile:    "membership.json",
+			secret:         testSecret,
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "invalid signature is rejected",
+			event:          "pull_request",
+			payloadFile:    "pull_request.json",
+			secret:         []byte("wrong-secret"),
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			s := New(Config{WebhookSecret: testSecret})
+			jobs := make(chan job, 1)
+			s.run = func(j job) error {
+				jobs <- j
+				return nil
+			}
+
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, signedRequest(t, tc.event, tc.payloadFile, tc.secret))
+			s.jobs.Wait()
+
+			if rec.Code != tc.expectedStatus {
+				t.Errorf("expected status %d, got %d", tc.expectedStatus, rec.Code)
+			}
+			select {
+			case j := <-jobs:
+				if tc.expectedJob == nil {
+					t.Errorf("expected no evaluation, got %+v", j)
+				} else if !reflect.DeepEqual(j, *tc.expectedJob) {
+					t.Errorf("expected job %+v, got %+v", *tc.expectedJob, j)
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tc.expectedJob != nil {
+					t.Errorf("expected evaluation of %+v", *tc.expectedJob)
+				}
+			}
+		})
+	}
+}
+
+func TestServeHTTPMethodNotAllowed(t *testing.T) {
+	s := New(Config{WebhookSecret: testSecret})
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
+
+func TestMembershipEventResetsCache(t *testing.T) {
+	s := New(Config{WebhookSecret: testSecret})
+	cache := s.currentCache()
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, signedRequest(t, "membership", "membership.json", testSecret))
+
+	if s.currentCache() == cache {
+		t.Error("expected the team cache to be replaced after a membership change")
+	}
+}
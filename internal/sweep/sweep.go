@@ -0,0 +1,216 @@
+package sweep
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v89/github"
+	"github.com/multimediallc/codeowners-plus/internal/app"
+	"github.com/multimediallc/codeowners-plus/internal/git"
+	gh "github.com/multimediallc/codeowners-plus/internal/github"
+)
+
+const defaultConcurrency = 4
+
+// Config configures a sweep of the open PRs in a repository
+type Config struct {
+	// App is the configuration each evaluation starts from; PR and RepoDir are set per PR
+	App app.Config
+	// CloneDir holds the bare clone of the repository
+	CloneDir string
+	// Base only sweeps PRs targeting this branch (all branches when empty)
+	Base string
+	// Labels only sweeps PRs with any of these labels (all PRs when empty)
+	Labels []string
+	// Concurrency is the maximum number of PRs evaluated at once
+	Concurrency int
+	// Log receives progress and the warnings of every evaluation
+	Log io.Writer
+}
+
+// Result is the outcome of evaluating a single PR
+type Result struct {
+	PR            int      `json:"pr"`
+	Title         string   `json:"title"`
+	URL           string   `json:"url"`
+	Success       bool     `json:"success"`
+	Message       string   `json:"message"`
+	StillRequired []string `json:"still_required"`
+	Error         string   `json:"error,omitempty"`
+	// Plan lists the GitHub changes which were skipped in dry-run mode
+	Plan []*app.PlannedAction `json:"plan,omitempty"`
+}
+
+// Report is the aggregate outcome of a sweep
+type Report struct {
+	Repo      string   `json:"repo"`
+	Total     int      `json:"total"`
+	Succeeded int      `json:"succeeded"`
+	Failed    int      `json:"failed"`
+	Errored   int      `json:"errored"`
+	Results   []Result `json:"results"`
+}
+
+// sharedCache is the cache of team membership and permission lookups shared by all evaluations: the
+// configured Cache, the CacheFile loaded once, or otherwise an in-memory cache
+func sharedCache(cfg app.Config) (gh.Cache, error) {
+	if cfg.Cache != nil {
+		return cfg.Cache, nil
+	}
+	if cfg.CacheFile != "" {
+		return gh.NewFileCache(cfg.CacheFile)
+	}
+	return gh.NewMemoryCache(), nil
+}
+
+// Run evaluates every matching open PR, at most Concurrency at a time.  Evaluation errors are
+// recorded in the report rather than stopping the sweep.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if cfg.Log == nil {
+		cfg.Log = io.Discard
+	}
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	client, err := app.NewClient(cfg.App)
+	if err != nil {
+		return nil, err
+	}
+	pulls, err := client.ListOpenPullRequests(cfg.Base, cfg.Labels)
+	if err != nil {
+		return nil, fmt.Errorf("error listing open PRs: %w", err)
+	}
+	_, _ = fmt.Fprintf(cfg.Log, "Sweeping %d open PR(s) in %s\n", len(pulls), cfg.App.Repo)
+
+	if len(pulls) == 0 {
+		return newReport(cfg.App.Repo, nil), nil
+	}
+
+	// Fetch every PR up front, since concurrent fetches into one clone would conflict
+	token, err := cfg.App.GitToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	clone := git.NewBareClone(filepath.Join(cfg.CloneDir, cfg.App.Repo+".git"), pulls[0].GetBase().GetRepo().GetCloneURL())
+	if err := clone.Fetch(token, refspecs(pulls)...); err != nil {
+		return nil, err
+	}
+
+	// Team membership is shared by all PRs, so it is only fetched once
+	appConfig := cfg.App
+	appConfig.RepoDir = clone.Dir()
+	if appConfig.Cache, err = sharedCache(cfg.App); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, len(pulls))
+	semaphore := make(chan struct{}, concurrency)
+	var logMu sync.Mutex
+	var wg sync.WaitGroup
+	for i, pull := range pulls {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			result, output := evaluate(appConfig, pull)
+			results[i] = result
+
+			logMu.Lock()
+			defer logMu.Unlock()
+			_, _ = io.Copy(cfg.Log, output)
+		}()
+	}
+	wg.Wait()
+
+	return newReport(cfg.App.Repo, results), nil
+}
+
+// newReport aggregates the results, ordered by PR number
+func newReport(repo string, results []Result) *Report {
+	report := &Report{Repo: repo, Results: append([]Result{}, results...)}
+	slices.SortFunc(report.Results, func(a, b Result) int { return a.PR - b.PR })
+	for _, result := range report.Results {
+		report.Total++
+		switch {
+		case result.Error != "":
+			report.Errored++
+		case result.Success:
+			report.Succeeded++
+		default:
+			report.Failed++
+		}
+	}
+	return report
+}
+
+// refspecs fetches the head of every PR along with the branches they target
+func refspecs(pulls []*github.PullRequest) []string {
+	specs := make([]string, 0, len(pulls)*2)
+	for _, pull := range pulls {
+		baseSpec := fmt.Sprintf("+refs/heads/%s:refs/heads/%s", pull.GetBase().GetRef(), pull.GetBase().GetRef())
+		if !slices.Contains(specs, baseSpec) {
+			specs = append(specs, baseSpec)
+		}
+		specs = append(specs, fmt.Sprintf("+refs/pull/%d/head:refs/pull/%d/head", pull.GetNumber(), pull.GetNumber()))
+	}
+	return specs
+}
+
+// evaluate runs the App pipeline for a single PR, returning its result and log output
+func evaluate(cfg app.Config, pull *github.PullRequest) (Result, *bytes.Buffer) {
+	result := Result{
+		PR:    pull.GetNumber(),
+		Title: pull.GetTitle(),
+		URL:   pull.GetHTMLURL(),
+	}
+	infoBuffer := &bytes.Buffer{}
+	warningBuffer := &bytes.Buffer{}
+	cfg.PR = pull.GetNumber()
+	cfg.InfoBuffer = infoBuffer
+	cfg.WarningBuffer = warningBuffer
+
+	output := &bytes.Buffer{}
+	defer func() {
+		prefix := fmt.Sprintf("[#%d] ", result.PR)
+		logLines(output, prefix, warningBuffer)
+		if cfg.Verbose {
+			logLines(output, prefix, infoBuffer)
+		}
+		if result.Error != "" {
+			_, _ = fmt.Fprintf(output, "%sERROR: %s\n", prefix, result.Error)
+		} else {
+			_, _ = fmt.Fprintf(output, "%s%s\n", prefix, strings.ReplaceAll(result.Message, "\n", " "))
+		}
+	}()
+
+	a, err := app.New(cfg)
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to initialize app: %v", err)
+		return result, output
+	}
+	outputData, err := a.Run()
+	if err != nil {
+		result.Error = err.Error()
+		return result, output
+	}
+	result.Success = outputData.Success
+	result.Message = outputData.Message
+	result.StillRequired = outputData.StillRequired
+	result.Plan = outputData.Plan
+	return result, output
+}
+
+func logLines(w io.Writer, prefix string, buffer *bytes.Buffer) {
+	for line := range strings.Lines(buffer.String()) {
+		_, _ = fmt.Fprintf(w, "%s%s\n", prefix, strings.TrimSuffix(line, "\n"))
+	}
+}
@@ -0,0 +1,118 @@
+package sweep
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/google/go-github/v89/github"
+	"github.com/multimediallc/codeowners-plus/internal/app"
+	gh "github.com/multimediallc/codeowners-plus/internal/github"
+)
+
+func TestRefspecs(t *testing.T) {
+	pulls := []*github.PullRequest{
+		{Number: github.Ptr(1), Base: &github.PullRequestBranch{Ref: github.Ptr("main")}},
+		{Number: github.Ptr(2), Base: &github.PullRequestBranch{Ref: github.Ptr("release")}},
+		{Number: github.Ptr(3), Base: &github.PullRequestBranch{Ref: github.Ptr("main")}},
+	}
+	expected := []string{
+		"+refs/heads/main:refs/heads/main",
+		"+refs/pull/1/head:refs/pull/1/head",
+		"+refs/heads/release:refs/heads/release",
+		"+refs/pull/2/head:refs/pull/2/head",
+		"+refs/pull/3/head:refs/pull/3/head",
+	}
+	if got := refspecs(pulls); !slices.Equal(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestSharedCache(t *testing.T) {
+	memoryCache := gh.NewMemoryCache()
+	cache, err := sharedCache(app.Config{Cache: memoryCache, CacheFile: "ignored.json"})
+	if err != nil || cache != memoryCache {
+		t.Errorf("expected the configured cache, got %T (%v)", cache, err)
+	}
+
+	cacheFile := filepath.Join(t.TempDir(), "cache.json")
+	cache, err = sharedCache(app.Config{CacheFile: cacheFile})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := cache.(*gh.FileCache); !ok {
+		t.Fatalf("expected a file cache, got %T", cache)
+	}
+	if err := cache.Set("key", &gh.CacheEntry{Body: []byte("{}")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(cacheFile); err != nil {
+		t.Errorf("expected the cache file to be written: %v", err)
+	}
+
+	cache, err = sharedCache(app.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := cache.(*gh.MemoryCache); !ok {
+		t.Errorf("expected a memory cache, got %T", cache)
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	results := []Result{
+		{PR: 3, Success: false, Message: "FAIL: Codeowners reviews not satisfied"},
+		{PR: 1, Success: true, Message: "Codeowners reviews satisfied"},
+		{PR: 2, Error: "InitPR Error: not found"},
+	}
+	report := newReport("owner/repo", results)
+
+	if report.Total != 3 || report.Succeeded != 1 || report.Failed != 1 || report.Errored != 1 {
+		t.Errorf("expected 3 total, 1 succeeded, 1 failed, 1 errored, got %+v", report)
+	}
+	prs := make([]int, 0, len(report.Results))
+	for _, result := range report.Results {
+		prs = append(prs, result.PR)
+	}
+	if !slices.Equal(prs, []int{1, 2, 3}) {
+		t.Errorf("expected results ordered by PR, got %v", prs)
+	}
+}
+
+func TestRunFiltersOpenPullRequests(t *testing.T) {
+	var query map[string][]string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/owner/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]*github.PullRequest{
+			{Number: github.Ptr(1), Labels: []*github.Label{{Name: github.Ptr("other")}}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	report, err := Run(context.Background(), Config{
+		App: app.Config{
+			Token:  "token",
+			Repo:   "owner/repo",
+			APIURL: server.URL + "/api/v3",
+		},
+		Base:   "main",
+		Labels: []string{"Needs-Sweep"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query["state"][0] != "open" || query["base"][0] != "main" {
+		t.Errorf("expected open PRs targeting main, got query %v", query)
+	}
+	if report.Total != 0 || len(report.Results) != 0 {
+		t.Errorf("expected PRs without the label to be skipped, got %+v", report)
+	}
+}
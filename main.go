@@ -2,35 +2,86 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/multimediallc/codeowners-plus/internal/app"
+	"github.com/multimediallc/codeowners-plus/internal/server"
+	sweeper "github.com/multimediallc/codeowners-plus/internal/sweep"
+	f "github.com/multimediallc/codeowners-plus/pkg/functional"
 )
 
 // Flags holds the command line flags
 type Flags struct {
-	Token   *string
-	RepoDir *string
-	PR      *int
-	Repo    *string
-	Verbose *bool
-	Quiet   *bool
+	Token             *string
+	RepoDir           *string
+	PR                *int
+	Repo              *string
+	Verbose           *bool
+	Quiet             *bool
+	CacheFile         *string
+	CacheTTL          *time.Duration
+	MaxRetries        *int
+	AppID             *int64
+	AppInstallationID *int64
+	AppPrivateKey     *string
+	APIURL            *string
+	UploadURL         *string
+	EventName         *string
+	EventPath         *string
+	BypassAuditLog    *string
+	DryRun            *bool
+	PlanFormat        *string
+	// serve subcommand flags
+	Addr          *string
+	WebhookSecret *string
+	CloneDir      *string
+	// sweep subcommand flags
+	Base        *string
+	Labels      *string
+	Concurrency *int
+	ReportFile  *string
 }
 
 var (
 	flags = &Flags{
-		Token:   flag.String("token", getEnv("INPUT_GITHUB-TOKEN", ""), "GitHub authentication token"),
-		RepoDir: flag.String("dir", getEnv("GITHUB_WORKSPACE", "/"), "Path to local Git repo"),
-		PR:      flag.Int("pr", ignoreError(strconv.Atoi(getEnv("INPUT_PR", ""))), "Pull Request number"),
-		Repo:    flag.String("repo", getEnv("INPUT_REPOSITORY", ""), "GitHub repo name"),
-		Verbose: flag.Bool("v", ignoreError(strconv.ParseBool(getEnv("INPUT_VERBOSE", "0"))), "Verbose output"),
-		Quiet:   flag.Bool("quiet", ignoreError(strconv.ParseBool(getEnv("INPUT_QUIET", "0"))), "Disable PR comments and review requests"),
+		Token:             flag.String("token", getEnv("INPUT_GITHUB-TOKEN", ""), "GitHub authentication token"),
+		RepoDir:           flag.String("dir", getEnv("GITHUB_WORKSPACE", "/"), "Path to local Git repo"),
+		PR:                flag.Int("pr", ignoreError(strconv.Atoi(getEnv("INPUT_PR", ""))), "Pull Request number"),
+		Repo:              flag.String("repo", getEnv("INPUT_REPOSITORY", ""), "GitHub repo name"),
+		Verbose:           flag.Bool("v", ignoreError(strconv.ParseBool(getEnv("INPUT_VERBOSE", "0"))), "Verbose output"),
+		Quiet:             flag.Bool("quiet", ignoreError(strconv.ParseBool(getEnv("INPUT_QUIET", "0"))), "Disable PR comments and review requests"),
+		CacheFile:         flag.String("cache-file", getEnv("INPUT_CACHE-FILE", ""), "Path to a file caching team membership and permission lookups between runs"),
+		CacheTTL:          flag.Duration("cache-ttl", ignoreError(time.ParseDuration(getEnv("INPUT_CACHE-TTL", "1h"))), "How long cached lookups are used before being revalidated"),
+		MaxRetries:        flag.Int("max-retries", ignoreError(strconv.Atoi(getEnv("INPUT_MAX-RETRIES", "3"))), "Retries for transient GitHub API failures and rate limits"),
+		AppID:             flag.Int64("app-id", ignoreError(strconv.ParseInt(getEnv("INPUT_APP-ID", ""), 10, 64)), "GitHub App ID (authenticate as an app instead of with a token)"),
+		AppInstallationID: flag.Int64("app-installation-id", ignoreError(strconv.ParseInt(getEnv("INPUT_APP-INSTALLATION-ID", ""), 10, 64)), "GitHub App installation ID (looked up from the repo if not set)"),
+		AppPrivateKey:     flag.String("app-private-key", getEnv("INPUT_APP-PRIVATE-KEY", ""), "GitHub App private key (PEM contents or path to the key file)"),
+		APIURL:            flag.String("api-url", getEnv("INPUT_API-URL", getEnv("GITHUB_API_URL", "")), "GitHub API URL (for GitHub Enterprise Server)"),
+		UploadURL:         flag.String("upload-url", getEnv("INPUT_UPLOAD-URL", ""), "GitHub upload URL (for GitHub Enterprise Server, defaults to the api-url host)"),
+		EventName:         flag.String("event-name", getEnv("GITHUB_EVENT_NAME", ""), "GitHub event which triggered the run (merge_group and issue_comment runs resolve the PR from the event)"),
+		EventPath:         flag.String("event-path", getEnv("GITHUB_EVENT_PATH", ""), "Path to the GitHub event payload"),
+		BypassAuditLog:    flag.String("bypass-audit-log", getEnv("INPUT_BYPASS-AUDIT-LOG", ""), "Path to a file every applied admin bypass is appended to as a JSON line"),
+		DryRun:            flag.Bool("dry-run", ignoreError(strconv.ParseBool(getEnv("INPUT_DRY-RUN", "0"))), "Evaluate the PR without changing anything on GitHub, and print the planned changes"),
+		PlanFormat:        flag.String("plan-format", getEnv("INPUT_PLAN-FORMAT", app.PlanFormatText), "Format of the dry-run plan: text or json"),
+		Addr:              flag.String("addr", getEnv("CODEOWNERS_PLUS_ADDR", ":8080"), "Address the serve subcommand listens on"),
+		WebhookSecret:     flag.String("webhook-secret", getEnv("CODEOWNERS_PLUS_WEBHOOK_SECRET", ""), "Secret used to verify webhook signatures (serve subcommand)"),
+		CloneDir:          flag.String("clone-dir", getEnv("CODEOWNERS_PLUS_CLONE_DIR", filepath.Join(os.TempDir(), "codeowners-plus")), "Directory for bare clones of repositories (serve and sweep subcommands)"),
+		Base:              flag.String("base", "", "Only sweep PRs targeting this base branch (sweep subcommand)"),
+		Labels:            flag.String("labels", "", "Only sweep PRs with any of these comma separated labels (sweep subcommand)"),
+		Concurrency:       flag.Int("concurrency", 4, "Number of PRs evaluated at once (sweep subcommand)"),
+		ReportFile:        flag.String("report", "", "Path to write the JSON sweep report to, stdout when empty (sweep subcommand)"),
 	}
 	WarningBuffer = bytes.NewBuffer([]byte{})
 	InfoBuffer    = bytes.NewBuffer([]byte{})
@@ -45,12 +96,79 @@ func initFlags(flags *Flags) error {
 
 	// Validate required flags
 	badFlags := make([]string, 0, 4)
-	if *flags.Token == "" {
+	usingApp := flags.AppID != nil && *flags.AppID != 0
+	if *flags.Token == "" && !usingApp {
 		badFlags = append(badFlags, "token")
 	}
-	if *flags.PR == 0 {
+	if usingApp && *flags.AppPrivateKey == "" {
+		badFlags = append(badFlags, "app-private-key")
+	}
+	// Merge queue and slash command runs may have no PR input, the PR is resolved from the
+	// merge_group or issue_comment event instead
+	fromEvent := flags.EventName != nil && (*flags.EventName == app.MergeGroupEventName || *flags.EventName == app.IssueCommentEventName)
+	if *flags.PR == 0 && !fromEvent {
 		badFlags = append(badFlags, "pr")
 	}
+	if fromEvent && *flags.EventPath == "" {
+		badFlags = append(badFlags, "event-path")
+	}
+	if *flags.Repo == "" {
+		badFlags = append(badFlags, "repo")
+	}
+	if len(badFlags) > 0 {
+		return fmt.Errorf("required flags or environment variables not set: %s", badFlags)
+	}
+	if flags.PlanFormat != nil && *flags.PlanFormat != app.PlanFormatText && *flags.PlanFormat != app.PlanFormatJSON {
+		return fmt.Errorf("invalid plan-format %q: expected %s or %s", *flags.PlanFormat, app.PlanFormatText, app.PlanFormatJSON)
+	}
+
+	return nil
+}
+
+// initServeFlags parses the flags following the serve subcommand
+func initServeFlags(flags *Flags, args []string) error {
+	// Only parse flags if we're not testing
+	if !testing.Testing() {
+		if err := flag.CommandLine.Parse(args); err != nil {
+			return err
+		}
+	}
+
+	badFlags := make([]string, 0, 3)
+	usingApp := flags.AppID != nil && *flags.AppID != 0
+	if *flags.Token == "" && !usingApp {
+		badFlags = append(badFlags, "token")
+	}
+	if usingApp && *flags.AppPrivateKey == "" {
+		badFlags = append(badFlags, "app-private-key")
+	}
+	if *flags.WebhookSecret == "" {
+		badFlags = append(badFlags, "webhook-secret")
+	}
+	if len(badFlags) > 0 {
+		return fmt.Errorf("required flags or environment variables not set: %s", badFlags)
+	}
+
+	return nil
+}
+
+// initSweepFlags parses the flags following the sweep subcommand
+func initSweepFlags(flags *Flags, args []string) error {
+	// Only parse flags if we're not testing
+	if !testing.Testing() {
+		if err := flag.CommandLine.Parse(args); err != nil {
+			return err
+		}
+	}
+
+	badFlags := make([]string, 0, 3)
+	usingApp := flags.AppID != nil && *flags.AppID != 0
+	if *flags.Token == "" && !usingApp {
+		badFlags = append(badFlags, "token")
+	}
+	if usingApp && *flags.AppPrivateKey == "" {
+		badFlags = append(badFlags, "app-private-key")
+	}
 	if *flags.Repo == "" {
 		badFlags = append(badFlags, "repo")
 	}
@@ -119,21 +237,152 @@ func writeGITHUBOUTPUT(outputData *app.OutputData) error {
 	return nil
 }
 
+// writePlan prints the GitHub changes planned in dry-run mode as text or JSON
+func writePlan(w io.Writer, plan []*app.PlannedAction, format string) error {
+	if format != app.PlanFormatJSON {
+		_, err := fmt.Fprint(w, app.FormatPlan(plan))
+		return err
+	}
+	if plan == nil {
+		plan = []*app.PlannedAction{}
+	}
+	jsonData, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling dry-run plan: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "%s\n", jsonData)
+	return err
+}
+
+// appConfig builds the app configuration shared by all subcommands from the flags
+func appConfig(flags *Flags) app.Config {
+	return app.Config{
+		Token:             *flags.Token,
+		RepoDir:           *flags.RepoDir,
+		PR:                *flags.PR,
+		Repo:              *flags.Repo,
+		Verbose:           *flags.Verbose,
+		Quiet:             *flags.Quiet,
+		CacheFile:         *flags.CacheFile,
+		CacheTTL:          *flags.CacheTTL,
+		MaxRetries:        *flags.MaxRetries,
+		AppID:             *flags.AppID,
+		AppInstallationID: *flags.AppInstallationID,
+		AppPrivateKey:     *flags.AppPrivateKey,
+		APIURL:            *flags.APIURL,
+		UploadURL:         *flags.UploadURL,
+		BypassAuditLog:    *flags.BypassAuditLog,
+		DryRun:            *flags.DryRun,
+		InfoBuffer:        InfoBuffer,
+		WarningBuffer:     WarningBuffer,
+	}
+}
+
+// serve runs the webhook server until interrupted
+func serve(args []string) {
+	if err := initServeFlags(flags, args); err != nil {
+		outputAndExit(os.Stderr, true, fmt.Sprintln(err))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	s := server.New(server.Config{
+		Addr:          *flags.Addr,
+		WebhookSecret: []byte(*flags.WebhookSecret),
+		CloneDir:      *flags.CloneDir,
+		App:           appConfig(flags),
+		Log:           os.Stderr,
+	})
+	if err := s.ListenAndServe(ctx); err != nil {
+		outputAndExit(os.Stderr, true, fmt.Sprintln(err))
+	}
+	outputAndExit(os.Stderr, false, "")
+}
+
+// sweep evaluates every open PR in the repository and writes an aggregate JSON report
+func sweep(args []string) {
+	if err := initSweepFlags(flags, args); err != nil {
+		outputAndExit(os.Stderr, true, fmt.Sprintln(err))
+	}
+
+	var labels []string
+	if *flags.Labels != "" {
+		labels = f.Map(strings.Split(*flags.Labels, ","), strings.TrimSpace)
+	}
+	report, err := sweeper.Run(context.Background(), sweeper.Config{
+		App:         appConfig(flags),
+		CloneDir:    *flags.CloneDir,
+		Base:        *flags.Base,
+		Labels:      labels,
+		Concurrency: *flags.Concurrency,
+		Log:         os.Stderr,
+	})
+	if err != nil {
+		outputAndExit(os.Stderr, true, fmt.Sprintln(err))
+	}
+
+	if err := writeSweepReport(report, *flags.ReportFile); err != nil {
+		outputAndExit(os.Stderr, true, fmt.Sprintln(err))
+	}
+	message := fmt.Sprintf("Swept %d PR(s): %d satisfied, %d not satisfied, %d errored\n",
+		report.Total, report.Succeeded, report.Failed, report.Errored)
+	outputAndExit(os.Stderr, report.Errored > 0, message)
+}
+
+// writeSweepReport writes the JSON report to path, or stdout when path is empty
+func writeSweepReport(report *sweeper.Report, path string) error {
+	jsonData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling sweep report: %w", err)
+	}
+	jsonData = append(jsonData, '\n')
+	if path == "" {
+		_, err = os.Stdout.Write(jsonData)
+		return err
+	}
+	if err := os.WriteFile(path, jsonData, 0644); err != nil {
+		return fmt.Errorf("error writing sweep report: %w", err)
+	}
+	return nil
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+			serve(os.Args[2:])
+			return
+		case "sweep":
+			sweep(os.Args[2:])
+			return
+		}
+	}
+
 	err := initFlags(flags)
 	if err != nil {
 		outputAndExit(os.Stderr, true, fmt.Sprintln(err))
 	}
 
-	cfg := app.Config{
-		Token:         *flags.Token,
-		RepoDir:       *flags.RepoDir,
-		PR:            *flags.PR,
-		Repo:          *flags.Repo,
-		Verbose:       *flags.Verbose,
-		Quiet:         *flags.Quiet,
-		InfoBuffer:    InfoBuffer,
-		WarningBuffer: WarningBuffer,
+	cfg := appConfig(flags)
+	if *flags.EventName == app.MergeGroupEventName {
+		mergeGroup, err := app.ReadMergeGroupEvent(*flags.EventPath)
+		if err != nil {
+			outputAndExit(os.Stderr, true, fmt.Sprintln(err))
+		}
+		cfg.PR = mergeGroup.PR
+		cfg.MergeGroup = mergeGroup
+	}
+	if *flags.EventName == app.IssueCommentEventName {
+		command, err := app.ReadIssueCommentEvent(*flags.EventPath)
+		if err != nil {
+			outputAndExit(os.Stderr, true, fmt.Sprintln(err))
+		}
+		if command == nil {
+			outputAndExit(os.Stdout, false, "No codeowners command in the comment\n")
+			return
+		}
+		cfg.PR = command.PR
+		cfg.Command = command
 	}
 
 	app, err := app.New(cfg)
@@ -151,6 +400,12 @@ func main() {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 	}
 
+	if cfg.DryRun {
+		if err := writePlan(os.Stdout, outputData.Plan, *flags.PlanFormat); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+	}
+
 	var w io.Writer
 	if outputData.Success {
 		w = os.Stdout
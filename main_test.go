@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/multimediallc/codeowners-plus/internal/app"
+	sweeper "github.com/multimediallc/codeowners-plus/internal/sweep"
 )
 
 func init() {
@@ -107,6 +109,13 @@ func TestInitFlags(t *testing.T) {
 	repoStr := "owner/repo"
 	emptyStr := ""
 	zeroInt := 0
+	appID := int64(12345)
+	keyStr := "/path/to/key.pem"
+	mergeGroupEvent := "merge_group"
+	issueCommentEvent := "issue_comment"
+	eventPath := "/path/to/event.json"
+	jsonFormat := "json"
+	yamlFormat := "yaml"
 	tt := []struct {
 		name        string
 		flags       *Flags
@@ -148,6 +157,81 @@ func TestInitFlags(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "app credentials instead of token",
+			flags: &Flags{
+				Token:         &emptyStr,
+				PR:            &prInt,
+				Repo:          &repoStr,
+				AppID:         &appID,
+				AppPrivateKey: &keyStr,
+			},
+			expectError: false,
+		},
+		{
+			name: "merge group event without PR",
+			flags: &Flags{
+				Token:     &tokenStr,
+				PR:        &zeroInt,
+				Repo:      &repoStr,
+				EventName: &mergeGroupEvent,
+				EventPath: &eventPath,
+			},
+			expectError: false,
+		},
+		{
+			name: "merge group event without event path",
+			flags: &Flags{
+				Token:     &tokenStr,
+				PR:        &zeroInt,
+				Repo:      &repoStr,
+				EventName: &mergeGroupEvent,
+				EventPath: &emptyStr,
+			},
+			expectError: true,
+		},
+		{
+			name: "issue comment event without PR",
+			flags: &Flags{
+				Token:     &tokenStr,
+				PR:        &zeroInt,
+				Repo:      &repoStr,
+				EventName: &issueCommentEvent,
+				EventPath: &eventPath,
+			},
+			expectError: false,
+		},
+		{
+			name: "app id without private key",
+			flags: &Flags{
+				Token:         &emptyStr,
+				PR:            &prInt,
+				Repo:          &repoStr,
+				AppID:         &appID,
+				AppPrivateKey: &emptyStr,
+			},
+			expectError: true,
+		},
+		{
+			name: "json plan format",
+			flags: &Flags{
+				Token:      &tokenStr,
+				PR:         &prInt,
+				Repo:       &repoStr,
+				PlanFormat: &jsonFormat,
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid plan format",
+			flags: &Flags{
+				Token:      &tokenStr,
+				PR:         &prInt,
+				Repo:       &repoStr,
+				PlanFormat: &yamlFormat,
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tc := range tt {
@@ -168,6 +252,168 @@ func TestInitFlags(t *testing.T) {
 	}
 }
 
+func TestInitServeFlags(t *testing.T) {
+	tokenStr := "test-token"
+	secretStr := "webhook-secret"
+	emptyStr := ""
+	appID := int64(12345)
+	keyStr := "/path/to/key.pem"
+	tt := []struct {
+		name        string
+		flags       *Flags
+		expectError bool
+	}{
+		{
+			name: "token and webhook secret set",
+			flags: &Flags{
+				Token:         &tokenStr,
+				WebhookSecret: &secretStr,
+			},
+			expectError: false,
+		},
+		{
+			name: "app credentials instead of token",
+			flags: &Flags{
+				Token:         &emptyStr,
+				AppID:         &appID,
+				AppPrivateKey: &keyStr,
+				WebhookSecret: &secretStr,
+			},
+			expectError: false,
+		},
+		{
+			name: "missing webhook secret",
+			flags: &Flags{
+				Token:         &tokenStr,
+				WebhookSecret: &emptyStr,
+			},
+			expectError: true,
+		},
+		{
+			name: "missing token",
+			flags: &Flags{
+				Token:         &emptyStr,
+				WebhookSecret: &secretStr,
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := initServeFlags(tc.flags, nil)
+			if tc.expectError && err == nil {
+				t.Error("expected error but got none")
+			} else if !tc.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestInitSweepFlags(t *testing.T) {
+	tokenStr := "test-token"
+	repoStr := "owner/repo"
+	emptyStr := ""
+	tt := []struct {
+		name        string
+		flags       *Flags
+		expectError bool
+	}{
+		{
+			name: "token and repo set",
+			flags: &Flags{
+				Token: &tokenStr,
+				Repo:  &repoStr,
+			},
+			expectError: false,
+		},
+		{
+			name: "missing repo",
+			flags: &Flags{
+				Token: &tokenStr,
+				Repo:  &emptyStr,
+			},
+			expectError: true,
+		},
+		{
+			name: "missing token",
+			flags: &Flags{
+				Token: &emptyStr,
+				Repo:  &repoStr,
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			err := initSweepFlags(tc.flags, nil)
+			if tc.expectError && err == nil {
+				t.Error("expected error but got none")
+			} else if !tc.expectError && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestWriteSweepReport(t *testing.T) {
+	reportPath := t.TempDir() + "/report.json"
+	report := &sweeper.Report{
+		Repo:      "owner/repo",
+		Total:     1,
+		Succeeded: 1,
+		Results:   []sweeper.Result{{PR: 1, Success: true, Message: "Codeowners reviews satisfied"}},
+	}
+	if err := writeSweepReport(report, reportPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed sweeper.Report
+	if err := json.Unmarshal(content, &parsed); err != nil {
+		t.Fatalf("failed to parse report: %v", err)
+	}
+	if parsed.Repo != "owner/repo" || parsed.Total != 1 || len(parsed.Results) != 1 || parsed.Results[0].PR != 1 {
+		t.Errorf("unexpected report: %+v", parsed)
+	}
+}
+
+func TestWritePlan(t *testing.T) {
+	plan := []*app.PlannedAction{{Action: "request_reviewers", Target: "@org/team"}}
+
+	var text bytes.Buffer
+	if err := writePlan(&text, plan, app.PlanFormatText); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text.String() != app.FormatPlan(plan) {
+		t.Errorf("expected text plan %q, got %q", app.FormatPlan(plan), text.String())
+	}
+
+	var jsonOutput bytes.Buffer
+	if err := writePlan(&jsonOutput, nil, app.PlanFormatJSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if jsonOutput.String() != "[]\n" {
+		t.Errorf("expected an empty JSON plan, got %q", jsonOutput.String())
+	}
+	jsonOutput.Reset()
+	if err := writePlan(&jsonOutput, plan, app.PlanFormatJSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var parsed []*app.PlannedAction
+	if err := json.Unmarshal(jsonOutput.Bytes(), &parsed); err != nil {
+		t.Fatalf("failed to parse plan: %v", err)
+	}
+	if len(parsed) != 1 || *parsed[0] != *plan[0] {
+		t.Errorf("expected plan %v, got %v", plan, parsed)
+	}
+}
+
 func TestOuputAndExit(t *testing.T) {
 	// Note: This test can't actually verify the exit behavior
 	// It only verifies that the buffers are written correctly
@@ -40,6 +40,10 @@ type CodeOwners interface {
 
 	// ApplyApprovals marks the given approvers as satisfied
 	ApplyApprovals(approvers []Slug)
+
+	// AddBackups adds backups to every required group the owner is in, so an
+	// approval from a backup satisfies the owner's groups
+	AddBackups(owner Slug, backups []Slug)
 }
 
 // New creates a new CodeOwners object from a root path and a list of diff files
@@ -135,6 +139,18 @@ func (om *ownersMap) ApplyApprovals(approvers []Slug) {
 	}
 }
 
+func (om *ownersMap) AddBackups(owner Slug, backups []Slug) {
+	for _, reviewers := range om.nameReviewerMap[owner.Normalized()] {
+		for _, backup := range backups {
+			if ContainsSlug(reviewers.Names, backup) {
+				continue
+			}
+			reviewers.Names = append(reviewers.Names, backup)
+			om.nameReviewerMap[backup.Normalized()] = append(om.nameReviewerMap[backup.Normalized()], reviewers)
+		}
+	}
+}
+
 type ownerTreeNode struct {
 	name                    string
 	parent                  *ownerTreeNode
@@ -3,6 +3,7 @@ package codeowners
 import (
 	"io"
 	"reflect"
+	"slices"
 	"testing"
 
 	f "github.com/multimediallc/codeowners-plus/pkg/functional"
@@ -550,3 +551,34 @@ func TestNameReviewerMapCaseInsensitive(t *testing.T) {
 		t.Errorf("Expected @frontend to be approved when @FRONTEND was used")
 	}
 }
+
+func TestAddBackups(t *testing.T) {
+	co := createMockCodeOwners(
+		map[string]ReviewerGroups{
+			"file.py": {{Names: NewSlugs([]string{"@alice"}), Approved: false}},
+			"file.go": {{Names: NewSlugs([]string{"@alice", "@bob"}), Approved: false}},
+			"file.js": {{Names: NewSlugs([]string{"@carol"}), Approved: false}},
+		},
+		map[string]ReviewerGroups{},
+		[]string{},
+	)
+	co.AddBackups(NewSlug("@Alice"), NewSlugs([]string{"@bob", "@dave"}))
+
+	for file, expected := range map[string][]string{
+		"file.py": {"@alice", "@bob", "@dave"},
+		"file.go": {"@alice", "@bob", "@dave"},
+		"file.js": {"@carol"},
+	} {
+		names := OriginalStrings(co.FileRequired()[file][0].Names)
+		if !slices.Equal(names, expected) {
+			t.Errorf("expected %s owners %v, got %v", file, expected, names)
+		}
+	}
+
+	// An approval from a backup satisfies the owner's groups
+	co.ApplyApprovals(NewSlugs([]string{"@dave"}))
+	allRequired := co.AllRequired()
+	if len(allRequired) != 1 || !allRequired[0].Names[0].EqualsString("@carol") {
+		t.Errorf("expected only @carol to be required, got %v", allRequired)
+	}
+}
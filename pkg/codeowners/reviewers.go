@@ -106,6 +106,35 @@ func FilterOutNames(names []Slug, exclude []Slug) []Slug {
 	})
 }
 
+// MinimalCover greedily picks names until every group contains a picked name (greedy set cover).
+// rank orders names by preference (lower is preferred) and takes precedence over coverage: among
+// the names with the best rank, the one in the most remaining groups is picked, then alphabetically.
+func (rgs ReviewerGroups) MinimalCover(rank func(Slug) int) []Slug {
+	remaining := ReviewerGroups(f.Filtered(rgs, func(rg *ReviewerGroup) bool { return len(rg.Names) > 0 }))
+	picked := make([]Slug, 0)
+	for len(remaining) > 0 {
+		candidates := remaining.Flatten()
+		slices.SortFunc(candidates, func(a, b Slug) int { return strings.Compare(a.Normalized(), b.Normalized()) })
+
+		best := candidates[0]
+		bestRank, bestCount := rank(best), remaining.count(best)
+		for _, candidate := range candidates[1:] {
+			candidateRank, candidateCount := rank(candidate), remaining.count(candidate)
+			if candidateRank < bestRank || (candidateRank == bestRank && candidateCount > bestCount) {
+				best, bestRank, bestCount = candidate, candidateRank, candidateCount
+			}
+		}
+		picked = append(picked, best)
+		remaining = remaining.FilterOut(best)
+	}
+	return picked
+}
+
+// count returns the number of groups containing name
+func (rgs ReviewerGroups) count(name Slug) int {
+	return len(f.Filtered(rgs, func(rg *ReviewerGroup) bool { return ContainsSlug(rg.Names, name) }))
+}
+
 func (rgs ReviewerGroups) ToCommentString(includeCheckbox bool) string {
 	ownersList := f.Map(rgs, func(s *ReviewerGroup) string {
 		prefix := "- "
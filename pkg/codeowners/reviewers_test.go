@@ -1,6 +1,7 @@
 package codeowners
 
 import (
+	"slices"
 	"sort"
 	"testing"
 
@@ -166,6 +167,64 @@ func TestReviewerGroupsFilter(t *testing.T) {
 	}
 }
 
+func TestReviewerGroupsMinimalCover(t *testing.T) {
+	noPreference := func(Slug) int { return 0 }
+	preferIndividuals := func(s Slug) int {
+		if s.IsTeam() {
+			return 1
+		}
+		return 0
+	}
+	tt := []struct {
+		name     string
+		groups   [][]string
+		rank     func(Slug) int
+		expected []string
+	}{
+		{
+			name:     "single name covering every group",
+			groups:   [][]string{{"@a", "@org/team"}, {"@b", "@org/team"}, {"@c", "@org/team"}},
+			rank:     noPreference,
+			expected: []string{"@org/team"},
+		},
+		{
+			name:     "preference outweighs coverage",
+			groups:   [][]string{{"@a", "@org/team"}, {"@b", "@org/team"}},
+			rank:     preferIndividuals,
+			expected: []string{"@a", "@b"},
+		},
+		{
+			name:     "ties are broken alphabetically",
+			groups:   [][]string{{"@b", "@a"}},
+			rank:     noPreference,
+			expected: []string{"@a"},
+		},
+		{
+			name:     "greedy picks the largest coverage first",
+			groups:   [][]string{{"@a", "@b"}, {"@b", "@c"}, {"@c", "@d"}, {"@d"}},
+			rank:     noPreference,
+			expected: []string{"@b", "@d"},
+		},
+		{
+			name:     "no groups",
+			groups:   [][]string{},
+			rank:     noPreference,
+			expected: []string{},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rgMan := NewReviewerGroupMemo()
+			rgs := f.Map(tc.groups, func(names []string) *ReviewerGroup { return rgMan.ToReviewerGroup(names...) })
+			got := OriginalStrings(ReviewerGroups(rgs).MinimalCover(tc.rank))
+			if !slices.Equal(got, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
 func TestReviewerGroupsContainsAny(t *testing.T) {
 	rgMan := NewReviewerGroupMemo()
 	rgs := ReviewerGroups{rgMan.ToReviewerGroup("@alice", "@bob"), rgMan.ToReviewerGroup("@charlie")}
@@ -52,6 +52,11 @@ func (s Slug) String() string {
 	return s.original
 }
 
+// IsTeam reports whether the slug is an org team (@org/team) rather than a user.
+func (s Slug) IsTeam() bool {
+	return strings.Contains(s.normalized, "/")
+}
+
 // MarshalJSON implements json.Marshaler for GitHub API compatibility.
 // Serializes as the original string to preserve case.
 func (s Slug) MarshalJSON() ([]byte, error) {
@@ -381,3 +381,23 @@ func TestContainsSlug(t *testing.T) {
 		})
 	}
 }
+
+func TestSlugIsTeam(t *testing.T) {
+	tt := []struct {
+		name     string
+		slug     string
+		expected bool
+	}{
+		{name: "user", slug: "@alice", expected: false},
+		{name: "team", slug: "@org/team", expected: true},
+		{name: "email", slug: "alice@example.com", expected: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NewSlug(tc.slug).IsTeam(); got != tc.expected {
+				t.Errorf("expected IsTeam %t for %s, got %t", tc.expected, tc.slug, got)
+			}
+		})
+	}
+}
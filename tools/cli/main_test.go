@@ -418,10 +418,11 @@ func (f *fakeCodeOwners) FileOptional() map[string]codeowners.ReviewerGroups {
 }
 func (f *fakeCodeOwners) SetAuthor(author string, mode codeowners.AuthorMode, authorTeams ...codeowners.Slug) {
 }
-func (f *fakeCodeOwners) AllRequired() codeowners.ReviewerGroups     { return nil }
-func (f *fakeCodeOwners) AllOptional() codeowners.ReviewerGroups     { return nil }
-func (f *fakeCodeOwners) UnownedFiles() []string                     { return nil }
-func (f *fakeCodeOwners) ApplyApprovals(approvers []codeowners.Slug) {}
+func (f *fakeCodeOwners) AllRequired() codeowners.ReviewerGroups                      { return nil }
+func (f *fakeCodeOwners) AllOptional() codeowners.ReviewerGroups                      { return nil }
+func (f *fakeCodeOwners) UnownedFiles() []string                                      { return nil }
+func (f *fakeCodeOwners) ApplyApprovals(approvers []codeowners.Slug)                  {}
+func (f *fakeCodeOwners) AddBackups(owner codeowners.Slug, backups []codeowners.Slug) {}
 
 func TestJsonTargets(t *testing.T) {
 	owners := &fakeCodeOwners{